@@ -1,65 +1,166 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"math/rand"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 
+	"github.com/danharasymiw/danban/server/auth"
+	"github.com/danharasymiw/danban/server/blob"
+	"github.com/danharasymiw/danban/server/blob/local"
+	"github.com/danharasymiw/danban/server/blob/s3"
 	"github.com/danharasymiw/danban/server/handlers"
+	"github.com/danharasymiw/danban/server/oidc"
+	"github.com/danharasymiw/danban/server/ratelimit"
+	"github.com/danharasymiw/danban/server/scheduler"
+	"github.com/danharasymiw/danban/server/store"
 	"github.com/danharasymiw/danban/server/store/mdb"
 )
 
 func main() {
 	storage := mdb.New()
 
+	var blobs blob.Storage
+	if os.Getenv("BLOB_STORAGE") == "s3" {
+		blobs = s3.New()
+	} else {
+		blobs = local.New()
+	}
+
+	go scheduler.New(storage).Run(context.Background())
+
+	// Visitors are told apart by IP address, which behind a proxy is only theirs when its headers are trusted.
+	limits := ratelimit.NewMemoryStore()
+	createLimiter := ratelimit.New(limits, "create", ratelimit.Limit{Burst: 10, Every: 2 * time.Minute})
+	mutationLimiter := ratelimit.New(limits, "mutate", ratelimit.Limit{Burst: 60, Every: 500 * time.Millisecond})
+
 	r := chi.NewRouter()
+	if os.Getenv("TRUST_PROXY_HEADERS") == "true" {
+		r.Use(middleware.RealIP)
+	}
 	r.Use(middleware.Logger)
+	r.Use(mutationLimiter.Mutations)
+	r.Use(auth.Middleware(storage))
 
-	handler := handlers.NewHandler(storage)
+	var oidcProvider *oidc.Provider
+	if config := oidc.ConfigFromEnv(); config != nil {
+		oidcProvider = oidc.NewProvider(config)
+	}
 
-	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
-		var boardName []byte
+	handler := handlers.NewHandler(storage, blobs, handlers.Options{
+		OIDC:             oidcProvider,
+		CreateLimiter:    createLimiter,
+		AutoCreateBoards: os.Getenv("AUTO_CREATE_BOARDS") == "true",
+		WordBoardNames:   os.Getenv("BOARD_NAMES") == "words",
+	})
 
-		const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-		// Loop to generate random characters
-		for i := 0; i < 12; i++ {
-			// Generate a random index in the charset
-			randomIndex := rand.Intn(len(charset))
-			// Append the randomly selected character to the result
-			boardName = append(boardName, charset[randomIndex])
-		}
+	// Everything but the API needs a CSRF token to change anything, the API checks its own bearer tokens instead.
+	web := r.With(auth.CSRFMiddleware)
 
-		redirectURL := fmt.Sprintf("/board/%s", boardName)
-		http.Redirect(w, r, redirectURL, http.StatusFound)
-	})
+	web.Get("/", handler.HandleHome)
+	web.Get("/board", handler.GoToBoard)
+	web.Get("/new", handler.NewBoardView)
+	web.Post("/new", handler.CreateBoard)
 
-	r.Get("/board", func(w http.ResponseWriter, r *http.Request) {
-		// Parse the query parameter 'name'
-		name := r.URL.Query().Get("name")
-		if name == "" {
-			http.Error(w, "Missing 'name' query parameter", http.StatusBadRequest)
-			return
-		}
+	// Everything on a board needs at least some role on it, see handlers.RequireRole.
+	viewer := web.With(handler.RequireRole(store.RoleViewer))
+	commenter := web.With(handler.RequireRole(store.RoleCommenter))
+	editor := web.With(handler.RequireRole(store.RoleEditor))
+	owner := web.With(handler.RequireRole(store.RoleOwner))
 
-		redirectURL := fmt.Sprintf("/board/%s", name)
-		http.Redirect(w, r, redirectURL, http.StatusFound)
-	})
-	r.Get("/board/{boardName}", handler.HandleBoard)
+	viewer.Get("/board/{boardName}", handler.HandleBoard)
+	viewer.Get("/board/{boardName}/card/{cardNumber}", handler.HandleCardByNumber)
+	viewer.Get("/board/{boardName}/archive", handler.HandleArchive)
+	editor.Put("/board/{boardName}/autoArchive", handler.SetAutoArchive)
+
+	editor.Post("/board/{boardName}/moveCard", handler.HandleMoveCard)
+
+	editor.Put("/board/{boardName}/column/{columnId}", handler.EditColumn)
+	editor.Post("/board/{boardName}/column/{columnId}/archive", handler.ArchiveColumnCards)
+
+	editor.Post("/board/{boardName}/column/{columnId}/cards/add", handler.AddCard)
+
+	viewer.Get("/board/{boardName}/column/{columnId}/card/{cardId}/edit", handler.EditCardView)
+	editor.Put("/board/{boardName}/column/{columnId}/card/{cardId}/edit", handler.UpdateCard)
 
-	r.Post("/board/{boardName}/moveCard", handler.HandleMoveCard)
+	editor.Delete("/board/{boardName}/column/{columnId}/card/{cardId}", handler.DeleteCard)
 
-	r.Post("/board/{boardName}/column/{columnId}/cards/add", handler.AddCard)
+	editor.Post("/board/{boardName}/column/{columnId}/card/{cardId}/archive", handler.ArchiveCard)
+	editor.Post("/board/{boardName}/column/{columnId}/card/{cardId}/restore", handler.RestoreCard)
 
-	r.Get("/board/{boardName}/column/{columnId}/card/{cardId}/edit", handler.EditCardView)
-	r.Put("/board/{boardName}/column/{columnId}/card/{cardId}/edit", handler.UpdateCard)
+	editor.Post("/board/{boardName}/column/{columnId}/card/{cardId}/checklists", handler.AddChecklist)
+	editor.Delete("/board/{boardName}/column/{columnId}/card/{cardId}/checklists/{checklistId}", handler.DeleteChecklist)
+	editor.Post("/board/{boardName}/column/{columnId}/card/{cardId}/checklists/{checklistId}/items", handler.AddChecklistItem)
+	editor.Put("/board/{boardName}/column/{columnId}/card/{cardId}/checklists/{checklistId}/items/{itemId}", handler.ToggleChecklistItem)
+	editor.Delete("/board/{boardName}/column/{columnId}/card/{cardId}/checklists/{checklistId}/items/{itemId}", handler.DeleteChecklistItem)
 
-	r.Delete("/board/{boardName}/column/{columnId}/card/{cardId}", handler.DeleteCard)
+	editor.Post("/board/{boardName}/column/{columnId}/card/{cardId}/links", handler.AddCardLink)
+	editor.Delete("/board/{boardName}/column/{columnId}/card/{cardId}/links/{linkId}", handler.DeleteCardLink)
 
-	r.Get("/about", handler.HandleAbout)
+	editor.Post("/board/{boardName}/column/{columnId}/card/{cardId}/attachments", handler.UploadAttachment)
+	viewer.Get("/board/{boardName}/column/{columnId}/card/{cardId}/attachments/{attachmentId}", handler.DownloadAttachment)
+	viewer.Get("/board/{boardName}/column/{columnId}/card/{cardId}/attachments/{attachmentId}/thumbnail", handler.AttachmentThumbnail)
+	editor.Delete("/board/{boardName}/column/{columnId}/card/{cardId}/attachments/{attachmentId}", handler.DeleteAttachment)
+
+	viewer.Get("/board/{boardName}/column/{columnId}/card/{cardId}/comments", handler.GetComments)
+	commenter.Post("/board/{boardName}/column/{columnId}/card/{cardId}/comments", handler.AddComment)
+	commenter.Get("/board/{boardName}/column/{columnId}/card/{cardId}/comments/{commentId}/edit", handler.EditCommentView)
+	commenter.Put("/board/{boardName}/column/{columnId}/card/{cardId}/comments/{commentId}", handler.UpdateComment)
+	commenter.Delete("/board/{boardName}/column/{columnId}/card/{cardId}/comments/{commentId}", handler.DeleteComment)
+
+	viewer.Get("/board/{boardName}/search", handler.SearchCards)
+
+	viewer.Get("/board/{boardName}/view/{viewId}", handler.HandleView)
+	editor.Post("/board/{boardName}/views", handler.AddView)
+	editor.Delete("/board/{boardName}/views/{viewId}", handler.DeleteView)
+
+	editor.Post("/board/{boardName}/labels", handler.AddLabel)
+	editor.Delete("/board/{boardName}/labels/{labelId}", handler.DeleteLabel)
+
+	editor.Post("/board/{boardName}/members", handler.AddMember)
+	editor.Delete("/board/{boardName}/members/{memberId}", handler.DeleteMember)
+	viewer.Post("/board/{boardName}/me", handler.SetMe)
+
+	viewer.Get("/board/{boardName}/roles", handler.HandleRoles)
+	owner.Post("/board/{boardName}/roles", handler.AddBoardRole)
+	// Only whoever made a board nobody owns can take ownership of it, see auth.BoardCreatorToken.
+	editor.Post("/board/{boardName}/roles/claim", handler.ClaimBoard)
+	owner.Put("/board/{boardName}/roles/{userId}", handler.EditBoardRole)
+	owner.Delete("/board/{boardName}/roles/{userId}", handler.DeleteBoardRole)
+
+	// The setup token is what stops anyone but the board's creator from setting its password.
+	editor.Post("/board/{boardName}/password", handler.SetBoardPassword)
+	web.Post("/board/{boardName}/unlock", handler.UnlockBoard)
+
+	web.Get("/board/{boardName}/shared/{token}", handler.OpenShareLink)
+	owner.Post("/board/{boardName}/shareLinks", handler.AddShareLink)
+	owner.Delete("/board/{boardName}/shareLinks/{linkId}", handler.DeleteShareLink)
+
+	web.Get("/about", handler.HandleAbout)
+
+	web.Get("/register", handler.RegisterView)
+	web.Post("/register", handler.Register)
+	web.Get("/login", handler.LoginView)
+	web.Post("/login", handler.Login)
+	web.Post("/logout", handler.Logout)
+	web.Get("/login/oidc", handler.OIDCLogin)
+	web.Get("/login/oidc/callback", handler.OIDCCallback)
+
+	web.Get("/account/tokens", handler.APITokensView)
+	web.Post("/account/tokens", handler.AddAPIToken)
+	web.Delete("/account/tokens/{tokenId}", handler.DeleteAPIToken)
+
+	// Scripts use the API with a token, see auth.TokenMiddleware.
+	r.Route("/api", func(api chi.Router) {
+		api.Use(auth.TokenMiddleware(storage))
+		api.With(handler.RequireRole(store.RoleViewer)).Get("/board/{boardName}", handler.APIGetBoard)
+		api.With(handler.RequireRole(store.RoleEditor)).Post("/board/{boardName}/cards", handler.APIAddCard)
+	})
 
 	r.Handle("/public/*", http.StripPrefix("/public/", http.FileServer(http.Dir("public"))))
 
@@ -0,0 +1,158 @@
+// Package auth handles user passwords and sessions, and finds who's making each request.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/danharasymiw/danban/server/logger"
+	"github.com/danharasymiw/danban/server/store"
+)
+
+const (
+	sessionCookieName = "danban-session"
+	// SessionLength is how long a login lasts.
+	SessionLength = 30 * 24 * time.Hour
+)
+
+type ctxKey string
+
+const ctxUser ctxKey = "user"
+
+// WithUser returns a copy of the context carrying the user making the request.
+func WithUser(ctx context.Context, user *store.User) context.Context {
+	return context.WithValue(ctx, ctxUser, user)
+}
+
+// UserFromContext returns the logged in user making the request, or nil for anonymous visitors.
+func UserFromContext(ctx context.Context) *store.User {
+	user, _ := ctx.Value(ctxUser).(*store.User)
+	return user
+}
+
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return ``, err
+	}
+	return string(hash), nil
+}
+
+// dummyPasswordHash is checked when there's no user or the user has no password, so that takes as long as checking
+// a real one and doesn't give away which emails have accounts.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("not anyone's password"), bcrypt.DefaultCost)
+
+// CheckPassword reports whether the password matches the user's hash. A nil user never matches.
+func CheckPassword(user *store.User, password string) bool {
+	if user == nil || user.PasswordHash == `` {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) == nil
+}
+
+// NewToken makes a random token to hand out, which should only be stored hashed with HashToken.
+func NewToken() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// StartSession logs the user in, setting a cookie that keeps them logged in for SessionLength.
+func StartSession(ctx context.Context, w http.ResponseWriter, r *http.Request, storage store.Storage, user *store.User) error {
+	token := NewToken()
+	now := time.Now()
+	err := storage.AddSession(ctx, &store.Session{
+		Id:        HashToken(token),
+		UserId:    user.Id,
+		CreatedAt: now,
+		ExpiresAt: now.Add(SessionLength),
+	})
+	if err != nil {
+		return err
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    token,
+		Path:     "/",
+		Expires:  now.Add(SessionLength),
+		HttpOnly: true,
+		Secure:   IsSecure(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
+
+// EndSession logs out whoever the request's session belongs to.
+func EndSession(ctx context.Context, w http.ResponseWriter, r *http.Request, storage store.Storage) error {
+	if cookie, err := r.Cookie(sessionCookieName); err == nil {
+		err = storage.DeleteSession(ctx, HashToken(cookie.Value))
+		if err != nil {
+			return err
+		}
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   IsSecure(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
+
+// IsSecure reports whether the request came in over https, including through a proxy.
+func IsSecure(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
+
+// Middleware attaches the logged in user to the request's context, see UserFromContext.
+func Middleware(storage store.Storage) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			cookie, err := r.Cookie(sessionCookieName)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			user, err := sessionUser(ctx, storage, cookie.Value)
+			if err != nil {
+				// A stale cookie just means they're logged out, anything else shouldn't lock everyone out either.
+				var notFound *store.NotFoundError
+				if !errors.As(err, &notFound) {
+					logger.New(ctx).WithError(err).Error("Failed to get session user")
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithUser(ctx, user)))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+func sessionUser(ctx context.Context, storage store.Storage, token string) (*store.User, error) {
+	session, err := storage.GetSession(ctx, HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	return storage.GetUser(ctx, session.UserId)
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	boardCookieName   = "danban-board"
+	creatorCookieName = "danban-creator"
+
+	// creatorCookieLength is how long whoever made a board has to take ownership of it.
+	creatorCookieLength = 365 * 24 * time.Hour
+)
+
+// cookieSecret signs the cookies that unlock password protected boards. Without COOKIE_SECRET a random one is made,
+// which logs everyone out of those boards whenever the server restarts.
+var cookieSecret = func() []byte {
+	if secret := os.Getenv("COOKIE_SECRET"); secret != `` {
+		return []byte(secret)
+	}
+	b := make([]byte, 32)
+	rand.Read(b)
+	return b
+}()
+
+// CheckBoardPassword reports whether the password matches the board's hash, see HashPassword.
+func CheckBoardPassword(passwordHash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(password)) == nil
+}
+
+// UnlockBoard sets a cookie that lets the visitor into the password protected board for SessionLength. Only the
+// board's own pages get the cookie, and changing the board's password makes it useless.
+func UnlockBoard(w http.ResponseWriter, r *http.Request, boardName, passwordHash string) {
+	expires := time.Now().Add(SessionLength)
+	http.SetCookie(w, &http.Cookie{
+		Name:     boardCookieName,
+		Value:    fmt.Sprintf("%d.%s", expires.Unix(), boardSignature(boardName, passwordHash, expires.Unix())),
+		Path:     fmt.Sprintf("/board/%s", boardName),
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   IsSecure(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// HasUnlockedBoard reports whether the request has a cookie from UnlockBoard for the board's current password.
+func HasUnlockedBoard(r *http.Request, boardName, passwordHash string) bool {
+	cookie, err := r.Cookie(boardCookieName)
+	if err != nil {
+		return false
+	}
+
+	expiresStr, signature, ok := strings.Cut(cookie.Value, ".")
+	if !ok {
+		return false
+	}
+	expires, err := strconv.ParseInt(expiresStr, 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(boardSignature(boardName, passwordHash, expires)))
+}
+
+func boardSignature(boardName, passwordHash string, expires int64) string {
+	mac := hmac.New(sha256.New, cookieSecret)
+	fmt.Fprintf(mac, "%s\n%s\n%d", boardName, passwordHash, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// RememberBoardCreator keeps the token a board was made with in a cookie for just that board, so whoever made it can
+// take ownership of it later, see BoardCreatorToken.
+func RememberBoardCreator(w http.ResponseWriter, r *http.Request, boardName, token string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     creatorCookieName,
+		Value:    token,
+		Path:     fmt.Sprintf("/board/%s", boardName),
+		Expires:  time.Now().Add(creatorCookieLength),
+		HttpOnly: true,
+		Secure:   IsSecure(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// BoardCreatorToken is the token from RememberBoardCreator, if the visitor made the board they're on.
+func BoardCreatorToken(r *http.Request) string {
+	cookie, err := r.Cookie(creatorCookieName)
+	if err != nil {
+		return ``
+	}
+	return cookie.Value
+}
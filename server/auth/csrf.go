@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/danharasymiw/danban/server/logger"
+)
+
+const (
+	csrfCookieName = "danban-csrf"
+	// CSRFHeader is where htmx and scripts on the page send the CSRF token, see CSRFToken.
+	CSRFHeader = "X-CSRF-Token"
+	// CSRFField is where plain forms send the CSRF token instead.
+	CSRFField = "csrf_token"
+)
+
+const ctxCSRFToken ctxKey = "csrfToken"
+
+// CSRFToken returns the token pages have to send back with any request that changes something, so other sites
+// can't make those requests on a visitor's behalf.
+func CSRFToken(ctx context.Context) string {
+	token, _ := ctx.Value(ctxCSRFToken).(string)
+	return token
+}
+
+// CSRFMiddleware gives every visitor a CSRF token in a cookie, and rejects requests that change something unless
+// they send the same token back in the CSRFHeader or CSRFField. Other sites can make a visitor's browser send the
+// cookie, but can't read it to send it back. The API doesn't use it, see TokenMiddleware.
+func CSRFMiddleware(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var token string
+		if cookie, err := r.Cookie(csrfCookieName); err == nil && cookie.Value != `` {
+			token = cookie.Value
+		}
+
+		if !isSafeMethod(r.Method) {
+			sent := r.Header.Get(CSRFHeader)
+			if sent == `` {
+				sent = r.PostFormValue(CSRFField)
+			}
+			if token == `` || subtle.ConstantTimeCompare([]byte(sent), []byte(token)) != 1 {
+				logger.New(ctx).Warn("Rejected request with a missing or wrong csrf token")
+				http.Error(w, "this page has expired, reload it and try again", http.StatusForbidden)
+				return
+			}
+		}
+
+		if token == `` {
+			token = NewToken()
+			http.SetCookie(w, &http.Cookie{
+				Name:     csrfCookieName,
+				Value:    token,
+				Path:     "/",
+				HttpOnly: true,
+				Secure:   IsSecure(r),
+				SameSite: http.SameSiteLaxMode,
+			})
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, ctxCSRFToken, token)))
+	}
+	return http.HandlerFunc(fn)
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCSRFMiddleware(t *testing.T) {
+	const token = "the-token"
+
+	tests := []struct {
+		name   string
+		method string
+		header string
+		field  string
+		bearer bool
+		want   int
+	}{
+		{name: "no token", method: http.MethodPost, want: http.StatusForbidden},
+		{name: "wrong header", method: http.MethodPost, header: "not-the-token", want: http.StatusForbidden},
+		{name: "wrong field", method: http.MethodPost, field: "not-the-token", want: http.StatusForbidden},
+		{name: "bearer token isn't enough", method: http.MethodPost, bearer: true, want: http.StatusForbidden},
+		{name: "matching header", method: http.MethodPost, header: token, want: http.StatusOK},
+		{name: "matching field", method: http.MethodPost, field: token, want: http.StatusOK},
+		{name: "matching header on delete", method: http.MethodDelete, header: token, want: http.StatusOK},
+		{name: "get", method: http.MethodGet, want: http.StatusOK},
+		{name: "head", method: http.MethodHead, want: http.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler := CSRFMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := CSRFToken(r.Context()); got != token {
+					t.Errorf("got token %q in the context, want %q", got, token)
+				}
+			}))
+
+			form := url.Values{}
+			if test.field != `` {
+				form.Set(CSRFField, test.field)
+			}
+			r := httptest.NewRequest(test.method, "/board/test/labels", strings.NewReader(form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			r.AddCookie(&http.Cookie{Name: csrfCookieName, Value: token})
+			if test.header != `` {
+				r.Header.Set(CSRFHeader, test.header)
+			}
+			if test.bearer {
+				r.Header.Set("Authorization", "Bearer "+APITokenPrefix+"whatever")
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != test.want {
+				t.Errorf("got status %d, want %d", w.Code, test.want)
+			}
+		})
+	}
+}
+
+func TestCSRFMiddlewareWithoutCookie(t *testing.T) {
+	handler := CSRFMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	// Sending a token doesn't help when it isn't the one in the visitor's cookie.
+	r := httptest.NewRequest(http.MethodPost, "/board/test/labels", nil)
+	r.Header.Set(CSRFHeader, "made-up")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusForbidden)
+	}
+
+	// Visitors without one get a cookie on their first page.
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if cookies := w.Result().Cookies(); len(cookies) != 1 || cookies[0].Name != csrfCookieName || cookies[0].Value == `` {
+		t.Errorf("got cookies %v, want a %s cookie", cookies, csrfCookieName)
+	}
+}
@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/danharasymiw/danban/server/logger"
+	"github.com/danharasymiw/danban/server/store"
+)
+
+// APITokenPrefix starts every API token, so they're easy to spot if they leak.
+const APITokenPrefix = "danban_"
+
+const ctxAPIToken ctxKey = "apiToken"
+
+// APITokenFromContext returns the API token the request was made with, or nil when it wasn't made with one.
+func APITokenFromContext(ctx context.Context) *store.APIToken {
+	token, _ := ctx.Value(ctxAPIToken).(*store.APIToken)
+	return token
+}
+
+// NewAPIToken makes a token to hand out for the API, which should only be stored hashed with HashToken.
+func NewAPIToken() string {
+	return APITokenPrefix + NewToken()
+}
+
+// TokenMiddleware only lets requests with a valid API token in their Authorization header through, attaching the
+// token and its user to the request's context. Cookies aren't enough, so the API can't be called from other sites
+// on a visitor's behalf.
+func TokenMiddleware(storage store.Storage) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || !strings.HasPrefix(bearer, APITokenPrefix) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="danban"`)
+				http.Error(w, "an API token is needed in the Authorization header", http.StatusUnauthorized)
+				return
+			}
+
+			token, user, err := tokenUser(ctx, storage, bearer)
+			var notFound *store.NotFoundError
+			if errors.As(err, &notFound) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="danban", error="invalid_token"`)
+				http.Error(w, "the API token is invalid or has expired", http.StatusUnauthorized)
+				return
+			}
+			if err != nil {
+				logger.New(ctx).WithError(err).Error("Failed to get api token user")
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
+
+			// Not knowing exactly when a token was last used isn't worth failing the request over.
+			err = storage.TouchAPIToken(ctx, token.Id, time.Now())
+			if err != nil {
+				logger.New(ctx).WithError(err).Warn("Failed to update api token last used")
+			}
+
+			ctx = context.WithValue(WithUser(ctx, user), ctxAPIToken, token)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+func tokenUser(ctx context.Context, storage store.Storage, bearer string) (*store.APIToken, *store.User, error) {
+	token, err := storage.GetAPITokenByHash(ctx, HashToken(bearer))
+	if err != nil {
+		return nil, nil, err
+	}
+	user, err := storage.GetUser(ctx, token.UserId)
+	if err != nil {
+		return nil, nil, err
+	}
+	return token, user, nil
+}
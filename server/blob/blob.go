@@ -0,0 +1,17 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned when there is no blob stored under a key.
+var ErrNotFound = errors.New("blob not found")
+
+// Storage keeps the contents of files, like card attachments, outside of the database.
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
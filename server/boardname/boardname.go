@@ -0,0 +1,62 @@
+// Package boardname makes up names for new boards and checks that names are ones a board can have.
+package boardname
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/danharasymiw/danban/server/store"
+)
+
+const (
+	MinLength = 4
+	MaxLength = 32
+
+	// Pattern is the characters a board name can have, in a form inputs can use too.
+	Pattern = `[A-Za-z0-9_\-]+`
+
+	// randomLength gives 62^12, about 2^71, possible random names, too many to guess one.
+	randomLength = 12
+	charset      = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+var validName = regexp.MustCompile(`^` + Pattern + `$`)
+
+// Check returns a bad request error saying what's wrong with the name, if anything.
+func Check(name string) error {
+	if len(name) < MinLength || len(name) > MaxLength {
+		return store.NewBadRequestError(fmt.Sprintf("board name must be between %d and %d characters", MinLength, MaxLength))
+	}
+	if !validName.MatchString(name) {
+		return store.NewBadRequestError("board name can only have letters, numbers, dashes and underscores")
+	}
+	return nil
+}
+
+// Random makes a name of random letters and numbers.
+func Random() string {
+	var name strings.Builder
+	for range randomLength {
+		name.WriteByte(charset[randomInt(len(charset))])
+	}
+	return name.String()
+}
+
+// Words makes a name like brave-otter-42. They're easier to read out than random ones, but there are only a few
+// million of them, so boards named this way are much easier to stumble on.
+func Words() string {
+	return fmt.Sprintf("%s-%s-%d", adjectives[randomInt(len(adjectives))], animals[randomInt(len(animals))], 10+randomInt(990))
+}
+
+// randomInt is a random number from 0 up to but not including n.
+func randomInt(n int) int {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		// crypto/rand doesn't fail on any platform Go supports.
+		panic(err)
+	}
+	return int(i.Int64())
+}
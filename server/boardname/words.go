@@ -0,0 +1,21 @@
+package boardname
+
+var adjectives = []string{
+	"able", "bold", "brave", "bright", "brisk", "busy", "calm", "clever", "cosy", "crisp",
+	"curious", "daring", "eager", "early", "fancy", "fast", "fierce", "fluffy", "friendly", "gentle",
+	"giant", "glad", "golden", "grand", "happy", "hardy", "honest", "humble", "jolly", "keen",
+	"kind", "lively", "lucky", "merry", "mighty", "misty", "modest", "noble", "nimble", "patient",
+	"plucky", "polite", "proud", "quick", "quiet", "rapid", "ready", "rosy", "sharp", "shiny",
+	"silent", "sleepy", "smart", "snowy", "speedy", "spry", "steady", "sunny", "swift", "tidy",
+	"tiny", "vivid", "warm", "wise", "witty", "zesty",
+}
+
+var animals = []string{
+	"badger", "bat", "bear", "beaver", "bison", "camel", "cat", "cheetah", "crane", "crow",
+	"deer", "dingo", "dog", "dolphin", "donkey", "duck", "eagle", "eel", "falcon", "ferret",
+	"finch", "fox", "frog", "gecko", "goat", "goose", "hare", "hawk", "hedgehog", "heron",
+	"horse", "ibis", "koala", "lemur", "lion", "llama", "lynx", "magpie", "marten", "mole",
+	"moose", "mouse", "newt", "otter", "owl", "panda", "parrot", "pelican", "penguin", "pony",
+	"puffin", "quail", "rabbit", "raven", "robin", "seal", "shark", "sloth", "stoat", "swan",
+	"tiger", "toad", "turtle", "walrus", "wombat", "yak",
+}
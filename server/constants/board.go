@@ -0,0 +1,24 @@
+package constants
+
+const (
+	// BoardVisibilityOpen boards can be seen and changed by anyone with the link.
+	BoardVisibilityOpen = "open"
+	// BoardVisibilityPrivate boards are owned by whoever made them, who picks who else can see them.
+	BoardVisibilityPrivate = "private"
+
+	DefaultBoardTemplate = "simple"
+)
+
+// BoardTemplate is the columns a new board starts with. Cards moved to the last column are done.
+type BoardTemplate struct {
+	Name    string
+	Label   string
+	Columns []string
+}
+
+// BoardTemplates are the templates a new board can be made from.
+var BoardTemplates = []BoardTemplate{
+	{Name: "simple", Label: "To do, in progress, done", Columns: []string{"To do", "In Progress", "Done"}},
+	{Name: "kanban", Label: "Kanban", Columns: []string{"Backlog", "To do", "In Progress", "Review", "Done"}},
+	{Name: "week", Label: "Week planner", Columns: []string{"This week", "Today", "Done"}},
+}
@@ -4,4 +4,34 @@ const (
 	MinTitleLength       = 4
 	MaxTitleLength       = 128
 	MaxDescriptionLength = 2048
+	MaxLabelNameLength   = 24
+	MaxMemberNameLength  = 32
+	MaxLaneNameLength    = 32
+	MaxColumnNameLength  = 32
+
+	MaxChecklistNameLength = 64
+	MaxChecklistItemLength = 256
+
+	MaxCommentLength = 2048
+
+	MaxAttachmentSize  = 10 << 20
+	MaxAttachmentCount = 20
+	ThumbnailSize      = 200
+
+	MinSearchLength  = 2
+	MaxSearchLength  = 128
+	MaxSearchResults = 10
+
+	MaxViewNameLength = 32
+
+	MaxShareLinkNameLength = 32
+	MaxShareLinks          = 20
+
+	MaxAutoArchiveDays = 365
+
+	// DateFormat is the layout used by date inputs for card start and due dates.
+	DateFormat = "2006-01-02"
 )
+
+// LabelColors is the palette a board's labels can pick from.
+var LabelColors = []string{"gray", "red", "orange", "yellow", "green", "teal", "blue", "purple", "pink"}
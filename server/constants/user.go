@@ -0,0 +1,15 @@
+package constants
+
+const (
+	MaxEmailLength    = 254
+	MinPasswordLength = 8
+	// Bcrypt only looks at the first 72 bytes of a password.
+	MaxPasswordLength = 72
+
+	MaxAPITokenNameLength = 32
+	MaxAPITokenBoards     = 20
+	MaxAPITokens          = 20
+)
+
+// APITokenLifetimes are how many days an API token can be made to last for.
+var APITokenLifetimes = []int{7, 30, 90, 365}
@@ -0,0 +1,199 @@
+// Package filter parses the board filter query language, e.g. `label:bug assignee:me due:<7d -column:Done`.
+//
+// A query is made of space separated terms. A `key:value` term filters on a card field, anything else is a word that
+// has to appear in the card's title or description. Prefixing a term with `-` excludes the matching cards instead,
+// and values with spaces can be quoted like `label:"needs review"`.
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/danharasymiw/danban/server/constants"
+	"github.com/danharasymiw/danban/server/store"
+)
+
+const (
+	keyLabel    = "label"
+	keyAssignee = "assignee"
+	keyColumn   = "column"
+	keyDue      = "due"
+
+	valueMe   = "me"
+	valueNone = "none"
+	valueAny  = "any"
+
+	dueOverdue = "overdue"
+)
+
+// Parse turns the query into a card filter, resolving assignee:me to the member id me. An empty query has no filter.
+func Parse(query, me string, now time.Time) (*store.CardFilter, error) {
+	terms, err := tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	f := &store.CardFilter{}
+	for _, term := range terms {
+		exclude := strings.HasPrefix(term, "-") && len(term) > 1
+		if exclude {
+			term = term[1:]
+		}
+
+		key, value, ok := strings.Cut(term, ":")
+		if !ok {
+			if exclude {
+				f.ExcludeWords = append(f.ExcludeWords, term)
+			} else {
+				f.Words = append(f.Words, term)
+			}
+			continue
+		}
+		if value == `` {
+			return nil, store.NewBadRequestError(fmt.Sprintf("%s: needs a value", key))
+		}
+
+		switch strings.ToLower(key) {
+		case keyLabel:
+			switch strings.ToLower(value) {
+			case valueNone:
+				f.HasLabels = boolPtr(exclude)
+			case valueAny:
+				f.HasLabels = boolPtr(!exclude)
+			default:
+				addValue(&f.LabelNames, &f.ExcludeLabelNames, value, exclude)
+			}
+		case keyAssignee:
+			switch strings.ToLower(value) {
+			case valueNone:
+				f.HasAssignees = boolPtr(exclude)
+			case valueAny:
+				f.HasAssignees = boolPtr(!exclude)
+			case valueMe:
+				if me == `` {
+					return nil, store.NewBadRequestError("pick who you are before filtering by assignee:me")
+				}
+				addValue(&f.AssigneeIds, &f.ExcludeAssigneeIds, me, exclude)
+			default:
+				addValue(&f.AssigneeNames, &f.ExcludeAssigneeNames, value, exclude)
+			}
+		case keyColumn:
+			addValue(&f.ColumnNames, &f.ExcludeColumnNames, value, exclude)
+		case keyDue:
+			err = parseDue(f, value, exclude, now)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, store.NewBadRequestError(fmt.Sprintf("unknown filter %s:, try label:, assignee:, column: or due:", key))
+		}
+	}
+	return f, nil
+}
+
+// parseDue handles due:none, due:any, due:overdue and comparisons like due:<7d, due:>2w or due:<2025-01-31.
+func parseDue(f *store.CardFilter, value string, exclude bool, now time.Time) error {
+	switch strings.ToLower(value) {
+	case valueNone:
+		f.HasDueDate = boolPtr(exclude)
+		return nil
+	case valueAny:
+		f.HasDueDate = boolPtr(!exclude)
+		return nil
+	}
+
+	// Cards without a due date match neither side of a comparison, so negating one would be confusing.
+	if exclude {
+		return store.NewBadRequestError("only due:none and due:any can be excluded")
+	}
+
+	if strings.ToLower(value) == dueOverdue {
+		overdueBefore := store.OverdueBefore(now)
+		f.DueBefore = &overdueBefore
+		return nil
+	}
+
+	op, operand := value[:1], value[1:]
+	if op != "<" && op != ">" {
+		return store.NewBadRequestError(fmt.Sprintf("due:%s should look like due:<7d, due:>2w, due:<%s or due:overdue", value, constants.DateFormat))
+	}
+
+	at, err := parseDueTime(operand, now)
+	if err != nil {
+		return err
+	}
+	if op == "<" {
+		f.DueBefore = &at
+	} else {
+		f.DueAfter = &at
+	}
+	return nil
+}
+
+// parseDueTime reads either a date or a number of days or weeks from now.
+func parseDueTime(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(constants.DateFormat, s); err == nil {
+		return t, nil
+	}
+
+	unit := 24 * time.Hour
+	switch {
+	case strings.HasSuffix(s, "d"):
+		s = strings.TrimSuffix(s, "d")
+	case strings.HasSuffix(s, "w"):
+		s = strings.TrimSuffix(s, "w")
+		unit *= 7
+	default:
+		return time.Time{}, store.NewBadRequestError(fmt.Sprintf("unknown due date %q, use days like 7d, weeks like 2w or a date like %s", s, constants.DateFormat))
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return time.Time{}, store.NewBadRequestError(fmt.Sprintf("unknown due date %q, use days like 7d, weeks like 2w or a date like %s", s, constants.DateFormat))
+	}
+	return now.Add(time.Duration(n) * unit), nil
+}
+
+// tokenize splits the query on spaces, keeping quoted values together and dropping the quotes.
+func tokenize(query string) ([]string, error) {
+	var terms []string
+	var term strings.Builder
+	quoted := false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case !quoted && (r == ' ' || r == '\t' || r == '\n'):
+			if term.Len() > 0 {
+				terms = append(terms, term.String())
+				term.Reset()
+			}
+		default:
+			term.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, store.NewBadRequestError("filter has an unclosed quote")
+	}
+	if term.Len() > 0 {
+		terms = append(terms, term.String())
+	}
+	return terms, nil
+}
+
+func addValue(include, exclude *[]string, value string, excluded bool) {
+	if excluded {
+		*exclude = append(*exclude, value)
+	} else {
+		*include = append(*include, value)
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,120 @@
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/danharasymiw/danban/server/store"
+)
+
+func TestParse(t *testing.T) {
+	const me = "64b7f0c2a1e2d3c4b5a69788"
+	now := time.Date(2025, 3, 14, 15, 30, 0, 0, time.UTC)
+	startOfToday := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+	inAWeek := now.Add(7 * 24 * time.Hour)
+	inTwoWeeks := now.Add(14 * 24 * time.Hour)
+	endOfMonth := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		query string
+		me    string
+		want  *store.CardFilter
+	}{
+		{name: "empty", query: "", want: nil},
+		{name: "only spaces", query: "  \t ", want: nil},
+		{name: "words", query: "login bug", want: &store.CardFilter{Words: []string{"login", "bug"}}},
+		{name: "excluded word", query: "-flaky", want: &store.CardFilter{ExcludeWords: []string{"flaky"}}},
+		{name: "lone dash is a word", query: "-", want: &store.CardFilter{Words: []string{"-"}}},
+		{
+			name:  "quoted value",
+			query: `label:"needs review"`,
+			want:  &store.CardFilter{LabelNames: []string{"needs review"}},
+		},
+		{
+			name:  "quoted words",
+			query: `"log in" -"sign up"`,
+			want:  &store.CardFilter{Words: []string{"log in"}, ExcludeWords: []string{"sign up"}},
+		},
+		{
+			name:  "labels",
+			query: "label:bug -label:wontfix LABEL:ui",
+			want:  &store.CardFilter{LabelNames: []string{"bug", "ui"}, ExcludeLabelNames: []string{"wontfix"}},
+		},
+		{name: "label:none", query: "label:none", want: &store.CardFilter{HasLabels: boolPtr(false)}},
+		{name: "-label:none", query: "-label:none", want: &store.CardFilter{HasLabels: boolPtr(true)}},
+		{name: "label:any", query: "label:any", want: &store.CardFilter{HasLabels: boolPtr(true)}},
+		{
+			name:  "assignee names",
+			query: "assignee:dan -assignee:sam",
+			want:  &store.CardFilter{AssigneeNames: []string{"dan"}, ExcludeAssigneeNames: []string{"sam"}},
+		},
+		{name: "assignee:me", query: "assignee:me", me: me, want: &store.CardFilter{AssigneeIds: []string{me}}},
+		{name: "-assignee:me", query: "-assignee:me", me: me, want: &store.CardFilter{ExcludeAssigneeIds: []string{me}}},
+		{name: "assignee:none", query: "assignee:none", want: &store.CardFilter{HasAssignees: boolPtr(false)}},
+		{
+			name:  "columns",
+			query: `column:Doing -column:"Won't do"`,
+			want:  &store.CardFilter{ColumnNames: []string{"Doing"}, ExcludeColumnNames: []string{"Won't do"}},
+		},
+		{name: "due:overdue", query: "due:overdue", want: &store.CardFilter{DueBefore: &startOfToday}},
+		{name: "due:none", query: "due:none", want: &store.CardFilter{HasDueDate: boolPtr(false)}},
+		{name: "-due:none", query: "-due:none", want: &store.CardFilter{HasDueDate: boolPtr(true)}},
+		{name: "due:any", query: "due:any", want: &store.CardFilter{HasDueDate: boolPtr(true)}},
+		{name: "due in days", query: "due:<7d", want: &store.CardFilter{DueBefore: &inAWeek}},
+		{name: "due in weeks", query: "due:>2w", want: &store.CardFilter{DueAfter: &inTwoWeeks}},
+		{name: "due before a date", query: "due:<2025-03-31", want: &store.CardFilter{DueBefore: &endOfMonth}},
+		{
+			name:  "everything",
+			query: `fix label:bug -column:Done due:overdue`,
+			want: &store.CardFilter{
+				Words:              []string{"fix"},
+				LabelNames:         []string{"bug"},
+				ExcludeColumnNames: []string{"Done"},
+				DueBefore:          &startOfToday,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Parse(test.query, test.me, now)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", test.query, err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Parse(%q) = %+v, want %+v", test.query, got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	now := time.Date(2025, 3, 14, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "unclosed quote", query: `label:"needs review`},
+		{name: "missing value", query: "label:"},
+		{name: "unknown key", query: "priority:high"},
+		{name: "assignee:me without me", query: "assignee:me"},
+		{name: "excluded due comparison", query: "-due:<7d"},
+		{name: "excluded overdue", query: "-due:overdue"},
+		{name: "due without comparison", query: "due:7d"},
+		{name: "due with unknown unit", query: "due:<7m"},
+		{name: "due with negative days", query: "due:<-3d"},
+		{name: "due with bad date", query: "due:<2025-02-30"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f, err := Parse(test.query, "", now)
+			var badRequest *store.BadRequestError
+			if !errors.As(err, &badRequest) {
+				t.Fatalf("Parse(%q) = %+v, %v, want a bad request error", test.query, f, err)
+			}
+		})
+	}
+}
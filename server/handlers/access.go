@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/danharasymiw/danban/server/auth"
+	"github.com/danharasymiw/danban/server/logger"
+	"github.com/danharasymiw/danban/server/store"
+	"github.com/danharasymiw/danban/server/ui/views"
+)
+
+type ctxKey string
+
+const ctxRole ctxKey = "role"
+
+// RequireRole only lets visitors with at least the role on the URL's board through, counting share links as viewers
+// for anyone who isn't already on the board and limiting API tokens to what they were made for. It also makes sure the column
+// and card in the URL, if any, are on that board, so a role on one board can't be used to reach into another.
+func (h *Handler) RequireRole(needed store.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			boardName := chi.URLParam(r, "boardName")
+
+			role, err := h.boardRole(r, boardName)
+			locked := errors.Is(err, errBoardLocked)
+			if !locked && thatWasAnError(ctx, w, "error getting board role", err) {
+				return
+			}
+			if role == `` {
+				role, err = h.shareRole(r, boardName)
+				if thatWasAnError(ctx, w, "error getting share link role", err) {
+					return
+				}
+			}
+			if token := auth.APITokenFromContext(ctx); token != nil {
+				role = token.Limit(boardName, role)
+			}
+			if !role.Allows(needed) {
+				if locked && role == `` {
+					askForBoardPassword(w, r, boardName, ``)
+				} else {
+					forbidden(w, r, roleProblem(ctx, role))
+				}
+				return
+			}
+
+			err = h.checkOnBoard(r, boardName)
+			if thatWasAnError(ctx, w, "error checking the url is on the board", err) {
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(withRole(ctx, role)))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+func withRole(ctx context.Context, role store.Role) context.Context {
+	return context.WithValue(ctx, ctxRole, role)
+}
+
+// requestRole is the visitor's role on the board, as worked out by RequireRole.
+func requestRole(r *http.Request) store.Role {
+	role, _ := r.Context().Value(ctxRole).(store.Role)
+	return role
+}
+
+// readOnly reports whether the visitor can only look at the board, so pages can leave out what they can't change.
+func readOnly(r *http.Request) bool {
+	return !requestRole(r).Allows(store.RoleEditor)
+}
+
+// errBoardLocked is returned by boardRole for boards locked with a password the visitor hasn't entered yet.
+var errBoardLocked = errors.New("board is locked with a password")
+
+// boardRole works out the visitor's role on the board. Boards nobody has been given a role on, including ones that
+// don't exist yet, are open for everyone to edit unless they're locked with a password.
+func (h *Handler) boardRole(r *http.Request, boardName string) (store.Role, error) {
+	ctx := r.Context()
+
+	roles, err := h.storage.GetBoardRoles(ctx, boardName)
+	var notFound *store.NotFoundError
+	if errors.As(err, &notFound) {
+		return store.RoleEditor, nil
+	}
+	if err != nil {
+		return ``, err
+	}
+	if len(roles) > 0 {
+		return roleFor(roles, auth.UserFromContext(ctx)), nil
+	}
+
+	passwordHash, err := h.storage.GetBoardPasswordHash(ctx, boardName)
+	if err != nil {
+		return ``, err
+	}
+	if passwordHash != `` && !auth.HasUnlockedBoard(r, boardName, passwordHash) {
+		return ``, errBoardLocked
+	}
+	return store.RoleEditor, nil
+}
+
+func roleFor(roles []*store.BoardRole, user *store.User) store.Role {
+	if len(roles) == 0 {
+		return store.RoleEditor
+	}
+	if user == nil {
+		return ``
+	}
+	idx := slices.IndexFunc(roles, func(role *store.BoardRole) bool { return role.UserId == user.Id })
+	if idx < 0 {
+		return ``
+	}
+	return roles[idx].Role
+}
+
+func roleProblem(ctx context.Context, role store.Role) string {
+	switch {
+	case role == `` && auth.UserFromContext(ctx) == nil:
+		return "this board is private, log in to see it"
+	case role == ``:
+		return "you haven't been added to this board, ask one of its owners to add you"
+	default:
+		return fmt.Sprintf("%ss can't do that on this board", role)
+	}
+}
+
+// checkOnBoard makes sure the column and card in the URL, if any, are on the board.
+func (h *Handler) checkOnBoard(r *http.Request, boardName string) error {
+	ctx := r.Context()
+	columnId := chi.URLParam(r, "columnId")
+	cardId := chi.URLParam(r, "cardId")
+
+	if columnId != `` {
+		err := h.columnsOnBoard(ctx, boardName, columnId)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cardId != `` {
+		card, err := h.storage.GetCard(ctx, cardId)
+		if err != nil {
+			return err
+		}
+		err = h.columnsOnBoard(ctx, boardName, card.ColumnId)
+		var notFound *store.NotFoundError
+		if errors.As(err, &notFound) {
+			return store.NewNotFoundError("card", cardId)
+		}
+		return err
+	}
+	return nil
+}
+
+// columnsOnBoard makes sure every one of the columns is on the board, for ids that came from the request.
+func (h *Handler) columnsOnBoard(ctx context.Context, boardName string, columnIds ...string) error {
+	columns, err := h.storage.GetColumns(ctx, boardName)
+	if err != nil {
+		return err
+	}
+
+	for _, columnId := range columnIds {
+		if !slices.ContainsFunc(columns, func(c *store.Column) bool { return c.Id == columnId }) {
+			return store.NewNotFoundError("column", columnId)
+		}
+	}
+	return nil
+}
+
+// wantsPage reports whether the request is for a whole page, rather than from htmx or a script using the API.
+func wantsPage(r *http.Request) bool {
+	if auth.APITokenFromContext(r.Context()) != nil {
+		return false
+	}
+	return r.Header.Get("HX-Request") != "true" || r.Header.Get("HX-Boosted") == "true"
+}
+
+// forbidden tells the visitor they aren't allowed to do that. Pages explain why, while any other request just gets
+// the reason, which htmx pops up as a toast.
+func forbidden(w http.ResponseWriter, r *http.Request, problem string) {
+	ctx := r.Context()
+	logger.New(ctx).Infof("Forbidden: %s", problem)
+
+	if !wantsPage(r) {
+		http.Error(w, problem, http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusForbidden)
+	views.Forbidden(problem, r.URL.RequestURI(), auth.UserFromContext(ctx) == nil).Render(ctx, w)
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/danharasymiw/danban/server/auth"
+	"github.com/danharasymiw/danban/server/constants"
+	"github.com/danharasymiw/danban/server/store"
+	"github.com/danharasymiw/danban/server/ui/views"
+)
+
+func (h *Handler) RegisterView(w http.ResponseWriter, r *http.Request) {
+	views.Register(``, r.URL.Query().Get("next")).Render(r.Context(), w)
+}
+
+func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	next := r.FormValue(`next`)
+
+	user, err := getFormUser(r)
+	if err == nil {
+		err = h.storage.AddUser(ctx, user)
+	}
+	var badRequest *store.BadRequestError
+	if errors.As(err, &badRequest) {
+		// Show the form again with what was wrong, rather than an error page.
+		views.Register(err.Error(), next).Render(ctx, w)
+		return
+	}
+	if thatWasAnError(ctx, w, "error adding user", err) {
+		return
+	}
+
+	err = auth.StartSession(ctx, w, r, h.storage, user)
+	if thatWasAnError(ctx, w, "error starting session", err) {
+		return
+	}
+
+	http.Redirect(w, r, safeRedirect(next), http.StatusSeeOther)
+}
+
+func (h *Handler) LoginView(w http.ResponseWriter, r *http.Request) {
+	views.Login(``, r.URL.Query().Get("next"), h.oidc != nil).Render(r.Context(), w)
+}
+
+func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	next := r.FormValue(`next`)
+
+	user, err := h.storage.GetUserByEmail(ctx, normalizeEmail(r.FormValue(`email`)))
+	var notFound *store.NotFoundError
+	if errors.As(err, &notFound) {
+		user, err = nil, nil
+	}
+	if thatWasAnError(ctx, w, "error getting user", err) {
+		return
+	}
+
+	// Unknown emails get the same answer, taking just as long, so they don't give away which emails have accounts.
+	if !auth.CheckPassword(user, r.FormValue(`password`)) {
+		views.Login("wrong email or password", next, h.oidc != nil).Render(ctx, w)
+		return
+	}
+
+	err = auth.StartSession(ctx, w, r, h.storage, user)
+	if thatWasAnError(ctx, w, "error starting session", err) {
+		return
+	}
+
+	http.Redirect(w, r, safeRedirect(next), http.StatusSeeOther)
+}
+
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	err := auth.EndSession(r.Context(), w, r, h.storage)
+	if thatWasAnError(r.Context(), w, "error ending session", err) {
+		return
+	}
+
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+func getFormUser(r *http.Request) (*store.User, error) {
+	email := normalizeEmail(r.FormValue(`email`))
+	if _, err := mail.ParseAddress(email); err != nil || len(email) > constants.MaxEmailLength {
+		return nil, store.NewBadRequestError(`enter a valid email address`)
+	}
+
+	name := strings.TrimSpace(r.FormValue(`name`))
+	if len(name) == 0 || len(name) > constants.MaxMemberNameLength {
+		return nil, store.NewBadRequestError(fmt.Sprintf(`name must be between 1 and %d characters`, constants.MaxMemberNameLength))
+	}
+
+	password := r.FormValue(`password`)
+	if len(password) < constants.MinPasswordLength || len(password) > constants.MaxPasswordLength {
+		return nil, store.NewBadRequestError(fmt.Sprintf(`password must be between %d and %d characters`, constants.MinPasswordLength, constants.MaxPasswordLength))
+	}
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return nil, fmt.Errorf("error hashing password: %w", err)
+	}
+
+	return &store.User{
+		Email:        email,
+		Name:         name,
+		PasswordHash: hash,
+		CreatedAt:    time.Now(),
+	}, nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// safeRedirect only follows redirects back to somewhere on this site, so login links can't send people elsewhere.
+func safeRedirect(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/"
+	}
+	return next
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/danharasymiw/danban/server/constants"
+	"github.com/danharasymiw/danban/server/logger"
+	"github.com/danharasymiw/danban/server/store"
+)
+
+// The API's JSON only has what scripts are likely to need, and stays the same when the storage types change.
+type apiBoard struct {
+	Name    string      `json:"name"`
+	Columns []apiColumn `json:"columns"`
+}
+
+type apiColumn struct {
+	Id    string    `json:"id"`
+	Name  string    `json:"name"`
+	Done  bool      `json:"done"`
+	Cards []apiCard `json:"cards"`
+}
+
+type apiCard struct {
+	Id          string     `json:"id"`
+	Ref         string     `json:"ref"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Labels      []string   `json:"labels"`
+	Assignees   []string   `json:"assignees"`
+	DueDate     *time.Time `json:"dueDate,omitempty"`
+}
+
+type apiAddCardRequest struct {
+	// Column is the id or name of the column to add the card to.
+	Column      string `json:"column"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// APIGetBoard returns the board's columns and cards.
+func (h *Handler) APIGetBoard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+
+	board, err := h.storage.GetBoard(ctx, boardName, nil)
+	if thatWasAnError(ctx, w, "error getting board", err) {
+		return
+	}
+
+	columns := make([]apiColumn, 0, len(board.Columns))
+	for _, column := range board.Columns {
+		cards := make([]apiCard, 0, len(column.Cards))
+		for _, card := range column.Cards {
+			cards = append(cards, toAPICard(boardName, card))
+		}
+		columns = append(columns, apiColumn{Id: column.Id, Name: column.Name, Done: column.Done, Cards: cards})
+	}
+
+	writeJSON(r, w, http.StatusOK, apiBoard{Name: board.Name, Columns: columns})
+}
+
+// APIAddCard adds a card to the bottom of a column.
+func (h *Handler) APIAddCard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+
+	var req apiAddCardRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		thatWasAnError(ctx, w, "error decoding request", store.NewBadRequestError("the request body must be a JSON card"))
+		return
+	}
+
+	if len(req.Title) < constants.MinTitleLength || len(req.Title) > constants.MaxTitleLength {
+		thatWasAnError(ctx, w, "invalid title", store.NewBadRequestError(fmt.Sprintf(`title must be between %d and %d characters`, constants.MinTitleLength, constants.MaxTitleLength)))
+		return
+	}
+	if len(req.Description) > constants.MaxDescriptionLength {
+		thatWasAnError(ctx, w, "invalid description", store.NewBadRequestError(fmt.Sprintf(`description cannot exceed %d characters`, constants.MaxDescriptionLength)))
+		return
+	}
+
+	columns, err := h.storage.GetColumns(ctx, boardName)
+	if thatWasAnError(ctx, w, "error getting board columns", err) {
+		return
+	}
+
+	idx := slices.IndexFunc(columns, func(c *store.Column) bool {
+		return c.Id == req.Column || strings.EqualFold(c.Name, strings.TrimSpace(req.Column))
+	})
+	if idx < 0 {
+		thatWasAnError(ctx, w, "unknown column", store.NewNotFoundError("column", req.Column))
+		return
+	}
+
+	card, err := h.storage.AddCard(ctx, columns[idx].Id, req.Title)
+	if thatWasAnError(ctx, w, "error adding card", err) {
+		return
+	}
+
+	if req.Description != `` {
+		card.Description = req.Description
+		err = h.storage.EditCard(ctx, card)
+		if thatWasAnError(ctx, w, "error adding card description", err) {
+			return
+		}
+	}
+
+	writeJSON(r, w, http.StatusCreated, toAPICard(boardName, card))
+}
+
+func toAPICard(boardName string, card *store.Card) apiCard {
+	labels := make([]string, 0, len(card.Labels))
+	for _, label := range card.Labels {
+		labels = append(labels, label.Name)
+	}
+	assignees := make([]string, 0, len(card.Assignees))
+	for _, member := range card.Assignees {
+		assignees = append(assignees, member.Name)
+	}
+
+	return apiCard{
+		Id:          card.Id,
+		Ref:         store.CardRef(boardName, card.Number),
+		Title:       card.Title,
+		Description: card.Description,
+		Labels:      labels,
+		Assignees:   assignees,
+		DueDate:     card.DueDate,
+	}
+}
+
+func writeJSON(r *http.Request, w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(body)
+	if err != nil {
+		logger.New(r.Context()).WithError(err).Error("Failed to write json response")
+	}
+}
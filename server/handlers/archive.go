@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/danharasymiw/danban/server/constants"
+	"github.com/danharasymiw/danban/server/store"
+	"github.com/danharasymiw/danban/server/ui/components"
+	"github.com/danharasymiw/danban/server/ui/views"
+)
+
+func (h *Handler) ArchiveCard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	cardId := chi.URLParam(r, "cardId")
+
+	err := h.storage.ArchiveCard(ctx, cardId)
+	if thatWasAnError(ctx, w, "error archiving card", err) {
+		return
+	}
+
+	components.RemovedCardComponent(cardId).Render(ctx, w)
+}
+
+// RestoreCard takes the card out of the archive, adding it back to the bottom of its column on the board.
+func (h *Handler) RestoreCard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+	cardId := chi.URLParam(r, "cardId")
+
+	err := h.storage.RestoreCard(ctx, cardId)
+	if thatWasAnError(ctx, w, "error restoring card", err) {
+		return
+	}
+
+	card, err := h.storage.GetCard(ctx, cardId)
+	if thatWasAnError(ctx, w, "error getting card from storage", err) {
+		return
+	}
+
+	components.MovedCardComponent(boardName, card.ColumnId, card).Render(ctx, w)
+}
+
+func (h *Handler) ArchiveColumnCards(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	columnId := chi.URLParam(r, "columnId")
+
+	_, err := h.storage.ArchiveColumnCards(ctx, columnId)
+	if thatWasAnError(ctx, w, "error archiving column cards", err) {
+		return
+	}
+
+	// Cards blocked by the archived ones aren't anymore, so the whole board needs redrawing.
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) HandleArchive(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+
+	board, err := h.storage.GetBoard(ctx, boardName, nil)
+	if thatWasAnError(ctx, w, "error getting board", err) {
+		return
+	}
+
+	cards, err := h.storage.GetArchivedCards(ctx, boardName)
+	if thatWasAnError(ctx, w, "error getting archived cards", err) {
+		return
+	}
+
+	views.Archive(boardName, board.AutoArchiveDays, cards).Render(ctx, w)
+}
+
+func (h *Handler) SetAutoArchive(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+
+	days, err := strconv.Atoi(r.FormValue(`autoArchiveDays`))
+	if err != nil || days < 0 || days > constants.MaxAutoArchiveDays {
+		thatWasAnError(ctx, w, "invalid auto archive days", store.NewBadRequestError(fmt.Sprintf(`auto archive days must be between 0 and %d`, constants.MaxAutoArchiveDays)))
+		return
+	}
+
+	err = h.storage.SetAutoArchiveDays(ctx, boardName, days)
+	if thatWasAnError(ctx, w, "error setting auto archive days", err) {
+		return
+	}
+
+	components.AutoArchiveSetting(boardName, days).Render(ctx, w)
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/danharasymiw/danban/server/blob"
+	"github.com/danharasymiw/danban/server/constants"
+	"github.com/danharasymiw/danban/server/logger"
+	"github.com/danharasymiw/danban/server/store"
+	"github.com/danharasymiw/danban/server/thumbnail"
+	"github.com/danharasymiw/danban/server/ui/components"
+)
+
+func (h *Handler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+	columnId := chi.URLParam(r, "columnId")
+	cardId := chi.URLParam(r, "cardId")
+
+	tooLarge := store.NewBadRequestError(fmt.Sprintf("attachments cannot exceed %d MB", constants.MaxAttachmentSize>>20))
+
+	// Leave some room for the rest of the multipart body.
+	r.Body = http.MaxBytesReader(w, r.Body, constants.MaxAttachmentSize+1<<20)
+	file, header, err := r.FormFile(`file`)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			err = tooLarge
+		} else {
+			err = store.NewBadRequestError("missing file")
+		}
+		thatWasAnError(ctx, w, "invalid attachment upload", err)
+		return
+	}
+	defer file.Close()
+
+	if header.Size > constants.MaxAttachmentSize {
+		thatWasAnError(ctx, w, "attachment too large", tooLarge)
+		return
+	}
+
+	card, err := h.storage.GetCard(ctx, cardId)
+	if thatWasAnError(ctx, w, "error getting card from storage", err) {
+		return
+	}
+	if len(card.Attachments) >= constants.MaxAttachmentCount {
+		thatWasAnError(ctx, w, "too many attachments", store.NewBadRequestError(fmt.Sprintf("cards cannot have more than %d attachments", constants.MaxAttachmentCount)))
+		return
+	}
+
+	data, err := io.ReadAll(file)
+	if thatWasAnError(ctx, w, "error reading attachment", err) {
+		return
+	}
+
+	// Don't trust the type the browser claims, look at the content instead.
+	attachment := &store.Attachment{
+		Name:        filepath.Base(header.Filename),
+		ContentType: http.DetectContentType(data),
+		Size:        int64(len(data)),
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	var thumb []byte
+	if thumbnail.Supported(attachment.ContentType) {
+		thumb, err = thumbnail.Make(data, constants.ThumbnailSize)
+		if err != nil {
+			logger.New(ctx).WithError(err).Warn("Unable to make attachment thumbnail")
+		}
+		attachment.HasThumbnail = err == nil
+	}
+
+	err = h.storage.AddAttachment(ctx, cardId, attachment)
+	if thatWasAnError(ctx, w, "error adding attachment", err) {
+		return
+	}
+
+	err = h.blobs.Put(ctx, attachmentKey(cardId, attachment.Id), bytes.NewReader(data), attachment.Size, attachment.ContentType)
+	if err == nil && attachment.HasThumbnail {
+		err = h.blobs.Put(ctx, thumbnailKey(cardId, attachment.Id), bytes.NewReader(thumb), int64(len(thumb)), "image/png")
+	}
+	if err != nil {
+		h.deleteAttachment(ctx, cardId, attachment)
+		thatWasAnError(ctx, w, "error storing attachment", err)
+		return
+	}
+
+	card.Attachments = append(card.Attachments, attachment)
+	components.CardAttachments(boardName, columnId, card, readOnly(r)).Render(ctx, w)
+	components.UpdatedCardComponent(boardName, columnId, card).Render(ctx, w)
+}
+
+func (h *Handler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	cardId := chi.URLParam(r, "cardId")
+
+	attachment, err := h.getAttachment(r)
+	if thatWasAnError(ctx, w, "error getting attachment", err) {
+		return
+	}
+
+	// Always download rather than display, so an uploaded page can't run scripts on this site.
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name}))
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	h.serveBlob(w, r, attachmentKey(cardId, attachment.Id), attachment.ContentType)
+}
+
+func (h *Handler) AttachmentThumbnail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	cardId := chi.URLParam(r, "cardId")
+
+	attachment, err := h.getAttachment(r)
+	if thatWasAnError(ctx, w, "error getting attachment", err) {
+		return
+	}
+	if !attachment.HasThumbnail {
+		thatWasAnError(ctx, w, "no thumbnail", store.NewNotFoundError("thumbnail", attachment.Id))
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, max-age=86400")
+	h.serveBlob(w, r, thumbnailKey(cardId, attachment.Id), "image/png")
+}
+
+func (h *Handler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+	columnId := chi.URLParam(r, "columnId")
+	cardId := chi.URLParam(r, "cardId")
+
+	attachment, err := h.getAttachment(r)
+	if thatWasAnError(ctx, w, "error getting attachment", err) {
+		return
+	}
+
+	err = h.deleteAttachment(ctx, cardId, attachment)
+	if thatWasAnError(ctx, w, "error deleting attachment", err) {
+		return
+	}
+
+	card, err := h.storage.GetCard(ctx, cardId)
+	if thatWasAnError(ctx, w, "error getting card from storage", err) {
+		return
+	}
+
+	components.CardAttachments(boardName, columnId, card, readOnly(r)).Render(ctx, w)
+	components.UpdatedCardComponent(boardName, columnId, card).Render(ctx, w)
+}
+
+func (h *Handler) getAttachment(r *http.Request) (*store.Attachment, error) {
+	attachmentId := chi.URLParam(r, "attachmentId")
+
+	card, err := h.storage.GetCard(r.Context(), chi.URLParam(r, "cardId"))
+	if err != nil {
+		return nil, err
+	}
+
+	idx := slices.IndexFunc(card.Attachments, func(a *store.Attachment) bool { return a.Id == attachmentId })
+	if idx < 0 {
+		return nil, store.NewNotFoundError("attachment", attachmentId)
+	}
+	return card.Attachments[idx], nil
+}
+
+// deleteAttachment removes the attachment from the card before its blobs, so a failure never leaves a broken link behind.
+func (h *Handler) deleteAttachment(ctx context.Context, cardId string, attachment *store.Attachment) error {
+	err := h.storage.DeleteAttachment(ctx, cardId, attachment.Id)
+	if err != nil {
+		return err
+	}
+
+	err = h.blobs.Delete(ctx, attachmentKey(cardId, attachment.Id))
+	if err != nil {
+		return err
+	}
+
+	if attachment.HasThumbnail {
+		return h.blobs.Delete(ctx, thumbnailKey(cardId, attachment.Id))
+	}
+	return nil
+}
+
+func (h *Handler) serveBlob(w http.ResponseWriter, r *http.Request, key, contentType string) {
+	ctx := r.Context()
+
+	content, err := h.blobs.Get(ctx, key)
+	if errors.Is(err, blob.ErrNotFound) {
+		err = store.NewNotFoundError("blob", key)
+	}
+	if thatWasAnError(ctx, w, "error getting blob", err) {
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	_, err = io.Copy(w, content)
+	if err != nil {
+		logger.New(ctx).WithError(err).Error("Failed to write blob to response")
+	}
+}
+
+func attachmentKey(cardId, attachmentId string) string {
+	return fmt.Sprintf("attachments/%s/%s", cardId, attachmentId)
+}
+
+func thumbnailKey(cardId, attachmentId string) string {
+	return fmt.Sprintf("attachments/%s/%s.thumbnail", cardId, attachmentId)
+}
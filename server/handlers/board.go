@@ -1,83 +1,226 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"slices"
+	"strconv"
+	"time"
 
+	"github.com/a-h/templ"
 	"github.com/go-chi/chi/v5"
 	"github.com/sirupsen/logrus"
 
+	"github.com/danharasymiw/danban/server/auth"
+	"github.com/danharasymiw/danban/server/boardname"
+	"github.com/danharasymiw/danban/server/constants"
+	"github.com/danharasymiw/danban/server/filter"
 	"github.com/danharasymiw/danban/server/logger"
 	"github.com/danharasymiw/danban/server/store"
+	"github.com/danharasymiw/danban/server/ui/components"
 	"github.com/danharasymiw/danban/server/ui/views"
 )
 
 func (h *Handler) HandleBoard(w http.ResponseWriter, r *http.Request) {
+	openCard, err := h.linkedCard(r)
+	if thatWasAnError(r.Context(), w, "error getting linked card", err) {
+		return
+	}
+
+	h.renderBoard(w, r, chi.URLParam(r, "boardName"), boardViewOptions(r), openCard)
+}
+
+// HandleView shows the board the way a saved view looks at it.
+func (h *Handler) HandleView(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+	viewId := chi.URLParam(r, "viewId")
+
+	view, err := h.storage.GetView(ctx, boardName, viewId)
+	if thatWasAnError(ctx, w, "error getting view", err) {
+		return
+	}
+
+	openCard, err := h.linkedCard(r)
+	if thatWasAnError(ctx, w, "error getting linked card", err) {
+		return
+	}
+
+	h.renderBoard(w, r, boardName, components.BoardViewOptions{
+		Me:        currentMemberId(r),
+		Assignee:  view.Assignee,
+		Sort:      view.Sort,
+		Lanes:     view.Lanes,
+		Filter:    view.Filter,
+		Collapsed: view.CollapsedColumnIds,
+		View:      view,
+		Role:      requestRole(r),
+	}, openCard)
+}
+
+// linkedCard gets the card a link to the board asked to open, if any.
+func (h *Handler) linkedCard(r *http.Request) (*store.Card, error) {
+	cardId := r.URL.Query().Get("card")
+	if cardId == `` {
+		return nil, nil
+	}
+	return h.storage.GetCard(r.Context(), cardId)
+}
+
+// HandleCardByNumber shows the board with the card's edit modal already open, for linking to a card by its number.
+func (h *Handler) HandleCardByNumber(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	boardName := chi.URLParam(r, "boardName")
 
+	number, err := strconv.Atoi(chi.URLParam(r, "cardNumber"))
+	if err != nil || number <= 0 {
+		thatWasAnError(ctx, w, "invalid card number", store.NewBadRequestError("card number must be a positive number"))
+		return
+	}
+
+	card, err := h.storage.GetCardByNumber(ctx, boardName, number)
+	if thatWasAnError(ctx, w, "error getting card by number", err) {
+		return
+	}
+
+	h.renderBoard(w, r, boardName, boardViewOptions(r), card)
+}
+
+// boardViewOptions reads how the board should be displayed from the request's query.
+func boardViewOptions(r *http.Request) components.BoardViewOptions {
+	query := r.URL.Query()
+	return components.BoardViewOptions{
+		Me:        currentMemberId(r),
+		Assignee:  query.Get("assignee"),
+		Sort:      query.Get("sort"),
+		Lanes:     query.Get("lanes"),
+		Filter:    query.Get("filter"),
+		Collapsed: query["collapsed"],
+		Role:      requestRole(r),
+	}
+}
+
+// renderBoard renders the whole board page, opening the edit modal for openCard when it's given.
+func (h *Handler) renderBoard(w http.ResponseWriter, r *http.Request, boardName string, opts components.BoardViewOptions, openCard *store.Card) {
+	ctx := r.Context()
+
 	log := logger.New(r.Context())
 	log.Infof("Received get board request")
 
-	if len(boardName) <= 3 || len(boardName) > 32 {
-		thatWasAnError(r.Context(), w, "invalid board name length", store.NewBadRequestError("board name must be between 3 and 32 characters"))
-		return
+	filter, err := boardFilter(opts)
+	if err != nil {
+		// Show the board anyway, with the problem next to the filter so it can be fixed.
+		opts.FilterError = err.Error()
 	}
 
-	board, err := h.storage.GetBoard(ctx, boardName)
+	board, err := h.storage.GetBoard(ctx, boardName, filter)
 	if err != nil {
 		if _, ok := err.(*store.NotFoundError); ok {
+			// Only new boards have to follow the naming rules, older ones keep whatever name they were made with.
+			if thatWasAnError(ctx, w, "invalid board name", boardname.Check(boardName)) {
+				return
+			}
+			if !h.autoCreateBoards {
+				log.Info("Board not found, asking whether to create it")
+				views.NewBoard(newBoardForm(r, boardName), ``, true).Render(ctx, w)
+				return
+			}
+			if !h.createLimiter.Allow(w, r) {
+				return
+			}
 			log.Info("Board not found, creating new board")
-			board, err = h.createNewBoard(ctx, boardName)
+			// Boards made by visiting them are open to everyone, like they always have been. Whoever made one can take
+			// ownership of it later.
+			board, opts.PasswordSetupToken, err = h.createNewBoard(ctx, boardName, constants.DefaultBoardTemplate, constants.BoardVisibilityOpen)
+			var badRequest *store.BadRequestError
+			if errors.As(err, &badRequest) {
+				// Someone else visited it at the same time and made it first. Go round again so their board is only
+				// shown to whoever it lets see it.
+				http.Redirect(w, r, r.URL.String(), http.StatusSeeOther)
+				return
+			}
 			if thatWasAnError(ctx, w, "failed to create board", err) {
 				return
 			}
+			if opts.PasswordSetupToken != `` {
+				auth.RememberBoardCreator(w, r, boardName, opts.PasswordSetupToken)
+			}
 		} else {
 			thatWasAnError(ctx, w, "failed to get board", err)
 			return
 		}
 	}
 
+	if opts.Sort == components.SortByDueDate {
+		sortCardsByDueDate(board)
+	}
+
+	var modal templ.Component
+	if openCard != nil {
+		if !slices.ContainsFunc(board.Columns, func(c *store.Column) bool { return c.Id == openCard.ColumnId }) {
+			thatWasAnError(ctx, w, "card is on another board", store.NewNotFoundError("card", openCard.Id))
+			return
+		}
+
+		modal, err = h.editCardModal(r, boardName, openCard.ColumnId, openCard)
+		if thatWasAnError(ctx, w, "error building edit card modal", err) {
+			return
+		}
+	}
+
 	log.Info("Board found, returning")
-	views.Board(board).Render(r.Context(), w)
+	views.Board(board, opts, modal).Render(r.Context(), w)
+}
+
+// sortCardsByDueDate orders each column's cards by due date, leaving cards without one at the bottom in their usual order.
+func sortCardsByDueDate(board *store.Board) {
+	for _, column := range board.Columns {
+		slices.SortStableFunc(column.Cards, func(a, b *store.Card) int {
+			switch {
+			case a.DueDate == nil && b.DueDate == nil:
+				return 0
+			case a.DueDate == nil:
+				return 1
+			case b.DueDate == nil:
+				return -1
+			default:
+				return a.DueDate.Compare(*b.DueDate)
+			}
+		})
+	}
 }
 
-func (h *Handler) createNewBoard(ctx context.Context, boardName string) (*store.Board, error) {
-	board := &store.Board{
-		Name: boardName,
-		Columns: []*store.Column{
-			{
-				Index: 0,
-				Name:  "To do",
-				Cards: []*store.Card{
-					{
-						Id:          "id",
-						Index:       0,
-						Title:       "Make cards",
-						Description: "This is a new board, make some cards!",
-					},
-				},
-			},
-			{
-				Index: 1,
-				Name:  "In Progress",
-				Cards: []*store.Card{},
-			},
-			{
-				Index: 2,
-				Name:  "Done",
-				Cards: []*store.Card{},
-			},
-		},
-	}
-	return board, h.storage.AddBoard(ctx, board)
+// boardFilter combines the filter query with the assignee picked in the toolbar, cards have to match both.
+// When the query can't be parsed the returned filter only has the assignee, along with the parse error.
+func boardFilter(opts components.BoardViewOptions) (*store.CardFilter, error) {
+	f, err := filter.Parse(opts.Filter, opts.Me, time.Now())
+
+	assignee := opts.Assignee
+	if assignee == "me" {
+		assignee = opts.Me
+	}
+	if assignee != `` {
+		if f == nil {
+			f = &store.CardFilter{}
+		}
+		f.AssigneeId = assignee
+	}
+	return f, err
 }
 
 type moveCardRequest struct {
 	CardId     string `json:"cardId"`
 	NewIndex   int    `json:"newIndex"`
 	ToColumnId string `json:"toColumnId"`
+
+	// Set when the board is split into swimlanes, see components.BoardLanes.
+	Lanes    string `json:"lanes,omitempty"`
+	FromLane string `json:"fromLane,omitempty"`
+	ToLane   string `json:"toLane,omitempty"`
+	// The cards either side of where the card was dropped in its lane, empty at either end.
+	BeforeCardId string `json:"beforeCardId,omitempty"`
+	AfterCardId  string `json:"afterCardId,omitempty"`
 }
 
 func (h *Handler) HandleMoveCard(w http.ResponseWriter, r *http.Request) {
@@ -98,10 +241,56 @@ func (h *Handler) HandleMoveCard(w http.ResponseWriter, r *http.Request) {
 	})
 	logEntry.Info("Found move card args")
 
+	card, err := h.storage.GetCard(ctx, req.CardId)
+	if thatWasAnError(ctx, w, "error getting card from storage", err) {
+		return
+	}
+
+	err = h.columnsOnBoard(ctx, chi.URLParam(r, "boardName"), card.ColumnId, req.ToColumnId)
+	if thatWasAnError(ctx, w, "card moved from or to another board", err) {
+		return
+	}
+
+	warning, err := h.blockedMoveWarning(ctx, card, req.ToColumnId)
+	if thatWasAnError(ctx, w, "error checking if card is blocked", err) {
+		return
+	}
+
+	// Everything about the move is checked before any of it is saved, so a bad lane can't leave it half done.
+	changedLane := false
+	if req.Lanes != `` {
+		req.NewIndex, err = h.laneDropIndex(ctx, card, req)
+		if thatWasAnError(ctx, w, "error finding where the card was dropped", err) {
+			return
+		}
+
+		changedLane, err = h.putCardInLane(ctx, chi.URLParam(r, "boardName"), card, req)
+		if thatWasAnError(ctx, w, "error moving card to lane", err) {
+			return
+		}
+	}
+
 	err = h.storage.MoveCard(ctx, req.ToColumnId, req.CardId, req.NewIndex)
 	if thatWasAnError(ctx, w, "error moving card in storage", err) {
 		return
 	}
 
+	if changedLane {
+		err = h.storage.EditCard(ctx, card)
+		if thatWasAnError(ctx, w, "error moving card to lane", err) {
+			return
+		}
+	}
+
+	if warning != `` {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(moveCardResponse{Warning: warning})
+		return
+	}
+
 	w.WriteHeader(http.StatusNoContent)
 }
+
+type moveCardResponse struct {
+	Warning string `json:"warning,omitempty"`
+}
@@ -3,10 +3,14 @@ package handlers
 import (
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/a-h/templ"
 	"github.com/go-chi/chi/v5"
 
 	"github.com/danharasymiw/danban/server/constants"
+	"github.com/danharasymiw/danban/server/logger"
 	"github.com/danharasymiw/danban/server/store"
 	"github.com/danharasymiw/danban/server/ui/components"
 )
@@ -41,11 +45,43 @@ func (h *Handler) EditCardView(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	columns, err := h.storage.GetColumns(r.Context(), boardName)
-	if thatWasAnError(ctx, w, "error getting board columns", err) {
+	modal, err := h.editCardModal(r, boardName, columnId, card)
+	if thatWasAnError(ctx, w, "error building edit card modal", err) {
 		return
 	}
-	components.EditCardModal(boardName, columnId, card, columns).Render(r.Context(), w)
+	modal.Render(ctx, w)
+}
+
+// editCardModal gathers everything the edit card modal shows alongside the card itself.
+func (h *Handler) editCardModal(r *http.Request, boardName, columnId string, card *store.Card) (templ.Component, error) {
+	ctx := r.Context()
+
+	columns, err := h.storage.GetColumns(ctx, boardName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting board columns: %w", err)
+	}
+
+	labels, err := h.storage.GetLabels(ctx, boardName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting board labels: %w", err)
+	}
+
+	members, err := h.storage.GetMembers(ctx, boardName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting board members: %w", err)
+	}
+
+	links, err := h.storage.GetCardLinks(ctx, card.Id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting card links: %w", err)
+	}
+
+	comments, err := h.storage.GetComments(ctx, card.Id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting card comments: %w", err)
+	}
+
+	return components.EditCardModal(boardName, columnId, card, columns, labels, members, links, comments, requestRole(r)), nil
 }
 
 func (h *Handler) UpdateCard(w http.ResponseWriter, r *http.Request) {
@@ -69,6 +105,43 @@ func (h *Handler) UpdateCard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	labels, err := h.storage.GetLabels(ctx, boardName)
+	if thatWasAnError(ctx, w, "error getting board labels", err) {
+		return
+	}
+
+	card.Labels, err = getFormCardLabels(r, labels)
+	if thatWasAnError(ctx, w, "invalid labels", err) {
+		return
+	}
+
+	members, err := h.storage.GetMembers(ctx, boardName)
+	if thatWasAnError(ctx, w, "error getting board members", err) {
+		return
+	}
+
+	card.Assignees, err = getFormCardAssignees(r, members)
+	if thatWasAnError(ctx, w, "invalid assignees", err) {
+		return
+	}
+
+	card.StartDate, card.DueDate, err = getFormCardDates(r)
+	if thatWasAnError(ctx, w, "invalid dates", err) {
+		return
+	}
+
+	card.Lane, err = getFormCardLane(r)
+	if thatWasAnError(ctx, w, "invalid lane", err) {
+		return
+	}
+
+	if r.FormValue("columnChanged") == "true" {
+		err = h.columnsOnBoard(ctx, boardName, r.FormValue("toColumnId"))
+		if thatWasAnError(ctx, w, "card moved to another board", err) {
+			return
+		}
+	}
+
 	err = h.storage.EditCard(r.Context(), card)
 	if thatWasAnError(ctx, w, "error editing card", err) {
 		return
@@ -77,10 +150,19 @@ func (h *Handler) UpdateCard(w http.ResponseWriter, r *http.Request) {
 	// User updated the card, we need to move it.
 	if r.FormValue("columnChanged") == "true" {
 		newColumnId := r.FormValue("toColumnId")
-		err := h.storage.MoveCard(r.Context(), newColumnId, cardId, -1)
+		warning, err := h.blockedMoveWarning(ctx, card, newColumnId)
+		if thatWasAnError(ctx, w, "error checking if card is blocked", err) {
+			return
+		}
+
+		err = h.storage.MoveCard(r.Context(), newColumnId, cardId, -1)
 		if thatWasAnError(ctx, w, "error moving card from edit card modal", err) {
 			return
 		}
+
+		if warning != `` {
+			triggerWarning(w, warning)
+		}
 		components.MovedCardComponent(boardName, newColumnId, card).Render(ctx, w)
 	} else {
 		components.CardComponent(boardName, columnId, card).Render(ctx, w)
@@ -105,6 +187,44 @@ func getFormCardDescription(r *http.Request, w http.ResponseWriter) (string, err
 	return description, nil
 }
 
+func getFormCardLane(r *http.Request) (string, error) {
+	lane := strings.TrimSpace(r.FormValue(`lane`))
+	if len(lane) > constants.MaxLaneNameLength {
+		return ``, store.NewBadRequestError(fmt.Sprintf(`lane cannot exceed %d characters`, constants.MaxLaneNameLength))
+	}
+	return lane, nil
+}
+
+func getFormCardDates(r *http.Request) (*time.Time, *time.Time, error) {
+	startDate, err := parseFormDate(r.FormValue(`startDate`))
+	if err != nil {
+		return nil, nil, store.NewBadRequestError(`start date must be formatted as YYYY-MM-DD`)
+	}
+
+	dueDate, err := parseFormDate(r.FormValue(`dueDate`))
+	if err != nil {
+		return nil, nil, store.NewBadRequestError(`due date must be formatted as YYYY-MM-DD`)
+	}
+
+	if startDate != nil && dueDate != nil && dueDate.Before(*startDate) {
+		return nil, nil, store.NewBadRequestError(`due date cannot be before the start date`)
+	}
+	return startDate, dueDate, nil
+}
+
+// parseFormDate parses a date input's value, an empty value clears the date.
+func parseFormDate(value string) (*time.Time, error) {
+	if value == `` {
+		return nil, nil
+	}
+
+	date, err := time.Parse(constants.DateFormat, value)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
 func getFormCard(r *http.Request, w http.ResponseWriter) (*store.Card, error) {
 	title, err := getFormCardTitle(r, w)
 	if err != nil {
@@ -138,5 +258,16 @@ func (h *Handler) DeleteCard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The card is already gone, so a leftover blob is only wasted space and not worth failing over.
+	for _, attachment := range card.Attachments {
+		err = h.blobs.Delete(ctx, attachmentKey(cardId, attachment.Id))
+		if err == nil && attachment.HasThumbnail {
+			err = h.blobs.Delete(ctx, thumbnailKey(cardId, attachment.Id))
+		}
+		if err != nil {
+			logger.New(ctx).WithError(err).Warn("Failed to delete attachment blob of deleted card")
+		}
+	}
+
 	w.WriteHeader(http.StatusOK)
 }
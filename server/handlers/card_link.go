@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/danharasymiw/danban/server/store"
+	"github.com/danharasymiw/danban/server/ui/components"
+)
+
+func (h *Handler) AddCardLink(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	cardId := chi.URLParam(r, "cardId")
+
+	typ := store.CardLinkType(r.FormValue(`linkType`))
+	if !slices.Contains(store.CardLinkTypes, typ) {
+		thatWasAnError(ctx, w, "invalid link type", store.NewBadRequestError(fmt.Sprintf(`unknown link type: %s`, typ)))
+		return
+	}
+
+	otherCardId, err := h.resolveCardReference(r, chi.URLParam(r, "boardName"), r.FormValue(`otherCard`))
+	if thatWasAnError(ctx, w, "invalid linked card", err) {
+		return
+	}
+
+	err = h.storage.AddCardLink(ctx, cardId, otherCardId, typ)
+	if thatWasAnError(ctx, w, "error adding card link", err) {
+		return
+	}
+
+	h.renderCardLinks(w, r)
+}
+
+func (h *Handler) DeleteCardLink(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	cardId := chi.URLParam(r, "cardId")
+	linkId := chi.URLParam(r, "linkId")
+
+	links, err := h.storage.GetCardLinks(ctx, cardId)
+	if thatWasAnError(ctx, w, "error getting card links", err) {
+		return
+	}
+	if !slices.ContainsFunc(links, func(l *store.CardLink) bool { return l.Id == linkId }) {
+		thatWasAnError(ctx, w, "link not on card", store.NewNotFoundError("card link", linkId))
+		return
+	}
+
+	err = h.storage.DeleteCardLink(ctx, linkId)
+	if thatWasAnError(ctx, w, "error deleting card link", err) {
+		return
+	}
+
+	h.renderCardLinks(w, r)
+}
+
+// renderCardLinks re-renders the links in the edit modal, along with the card on the board in case it's no longer blocked.
+func (h *Handler) renderCardLinks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+	columnId := chi.URLParam(r, "columnId")
+	cardId := chi.URLParam(r, "cardId")
+
+	card, err := h.storage.GetCard(ctx, cardId)
+	if thatWasAnError(ctx, w, "error getting card from storage", err) {
+		return
+	}
+
+	links, err := h.storage.GetCardLinks(ctx, cardId)
+	if thatWasAnError(ctx, w, "error getting card links", err) {
+		return
+	}
+
+	components.CardLinks(boardName, columnId, card.Id, links, readOnly(r)).Render(ctx, w)
+	components.UpdatedCardComponent(boardName, columnId, card).Render(ctx, w)
+}
+
+// resolveCardReference turns a reference to a card on the board, like 42, #42 or TEAM-42, into the card's id.
+// Anything else is taken to already be a card id, which is how cards on other boards are linked.
+func (h *Handler) resolveCardReference(r *http.Request, boardName, ref string) (string, error) {
+	ctx := r.Context()
+	ref = strings.TrimSpace(ref)
+	if ref == `` {
+		return ``, store.NewBadRequestError(`which card should this one be linked to?`)
+	}
+
+	numberStr := strings.TrimPrefix(ref, "#")
+	if prefix := strings.ToUpper(boardName) + "-"; len(ref) > len(prefix) && strings.EqualFold(ref[:len(prefix)], prefix) {
+		numberStr = ref[len(prefix):]
+	}
+
+	number, err := strconv.Atoi(numberStr)
+	if err != nil {
+		return ref, h.checkCanView(r, ref)
+	}
+
+	card, err := h.storage.GetCardByNumber(ctx, boardName, number)
+	if err != nil {
+		return ``, err
+	}
+	return card.Id, nil
+}
+
+// checkCanView makes sure the visitor can see the board a card is on, before it's linked to from another board.
+func (h *Handler) checkCanView(r *http.Request, cardId string) error {
+	ctx := r.Context()
+	card, err := h.storage.GetCard(ctx, cardId)
+	if err != nil {
+		return err
+	}
+
+	boardName, err := h.storage.GetColumnBoardName(ctx, card.ColumnId)
+	if err != nil {
+		return err
+	}
+
+	role, err := h.boardRole(r, boardName)
+	if err != nil && !errors.Is(err, errBoardLocked) {
+		return err
+	}
+	if !role.Allows(store.RoleViewer) {
+		return store.NewForbiddenError("you can't see the board that card is on")
+	}
+	return nil
+}
+
+// blockedMoveWarning explains why moving the card forward is a bad idea, if it's still blocked.
+func (h *Handler) blockedMoveWarning(ctx context.Context, card *store.Card, toColumnId string) (string, error) {
+	if !card.Blocked || card.ColumnId == toColumnId {
+		return ``, nil
+	}
+
+	fromColumn, err := h.storage.GetColumn(ctx, card.ColumnId)
+	if err != nil {
+		return ``, err
+	}
+	toColumn, err := h.storage.GetColumn(ctx, toColumnId)
+	if err != nil {
+		return ``, err
+	}
+
+	if toColumn.Index <= fromColumn.Index {
+		return ``, nil
+	}
+	return fmt.Sprintf("%q was moved to %s but it's still blocked by unfinished cards", card.Title, toColumn.Name), nil
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/danharasymiw/danban/server/constants"
+	"github.com/danharasymiw/danban/server/store"
+	"github.com/danharasymiw/danban/server/ui/components"
+)
+
+func (h *Handler) AddChecklist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	cardId := chi.URLParam(r, "cardId")
+
+	name := r.FormValue(`checklistName`)
+	if len(name) == 0 || len(name) > constants.MaxChecklistNameLength {
+		thatWasAnError(ctx, w, "invalid checklist name", store.NewBadRequestError(fmt.Sprintf(`checklist name must be between 1 and %d characters`, constants.MaxChecklistNameLength)))
+		return
+	}
+
+	err := h.storage.AddChecklist(ctx, cardId, &store.Checklist{Name: name})
+	if thatWasAnError(ctx, w, "error adding checklist", err) {
+		return
+	}
+
+	h.renderChecklists(w, r)
+}
+
+func (h *Handler) DeleteChecklist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	cardId := chi.URLParam(r, "cardId")
+	checklistId := chi.URLParam(r, "checklistId")
+
+	err := h.storage.DeleteChecklist(ctx, cardId, checklistId)
+	if thatWasAnError(ctx, w, "error deleting checklist", err) {
+		return
+	}
+
+	h.renderChecklists(w, r)
+}
+
+func (h *Handler) AddChecklistItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	cardId := chi.URLParam(r, "cardId")
+	checklistId := chi.URLParam(r, "checklistId")
+
+	text := r.FormValue(`itemText`)
+	if len(text) == 0 || len(text) > constants.MaxChecklistItemLength {
+		thatWasAnError(ctx, w, "invalid checklist item", store.NewBadRequestError(fmt.Sprintf(`checklist item must be between 1 and %d characters`, constants.MaxChecklistItemLength)))
+		return
+	}
+
+	err := h.storage.AddChecklistItem(ctx, cardId, checklistId, &store.ChecklistItem{Text: text})
+	if thatWasAnError(ctx, w, "error adding checklist item", err) {
+		return
+	}
+
+	h.renderChecklists(w, r)
+}
+
+func (h *Handler) ToggleChecklistItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	cardId := chi.URLParam(r, "cardId")
+	checklistId := chi.URLParam(r, "checklistId")
+	itemId := chi.URLParam(r, "itemId")
+
+	// Unchecked checkboxes aren't submitted at all.
+	done := r.FormValue(`done`) == "true"
+
+	err := h.storage.SetChecklistItemDone(ctx, cardId, checklistId, itemId, done)
+	if thatWasAnError(ctx, w, "error updating checklist item", err) {
+		return
+	}
+
+	h.renderChecklists(w, r)
+}
+
+func (h *Handler) DeleteChecklistItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	cardId := chi.URLParam(r, "cardId")
+	checklistId := chi.URLParam(r, "checklistId")
+	itemId := chi.URLParam(r, "itemId")
+
+	err := h.storage.DeleteChecklistItem(ctx, cardId, checklistId, itemId)
+	if thatWasAnError(ctx, w, "error deleting checklist item", err) {
+		return
+	}
+
+	h.renderChecklists(w, r)
+}
+
+// renderChecklists re-renders the checklists in the edit modal, along with the card on the board so its progress stays current.
+func (h *Handler) renderChecklists(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+	columnId := chi.URLParam(r, "columnId")
+	cardId := chi.URLParam(r, "cardId")
+
+	card, err := h.storage.GetCard(ctx, cardId)
+	if thatWasAnError(ctx, w, "error getting card from storage", err) {
+		return
+	}
+
+	components.CardChecklists(boardName, columnId, card, readOnly(r)).Render(ctx, w)
+	components.UpdatedCardComponent(boardName, columnId, card).Render(ctx, w)
+}
@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+	"slices"
 
+	"github.com/danharasymiw/danban/server/constants"
+	"github.com/danharasymiw/danban/server/store"
 	"github.com/danharasymiw/danban/server/ui/components"
 	"github.com/go-chi/chi/v5"
 )
@@ -12,7 +16,36 @@ func (h *Handler) AddColumn(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) EditColumn(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+	columnId := chi.URLParam(r, "columnId")
+
+	column, err := h.storage.GetColumn(ctx, columnId)
+	if thatWasAnError(ctx, w, "unable to get column", err) {
+		return
+	}
+
+	if name := r.FormValue("name"); name != `` {
+		if len(name) > constants.MaxColumnNameLength {
+			thatWasAnError(ctx, w, "invalid column name", store.NewBadRequestError(fmt.Sprintf(`column name must be between 1 and %d characters`, constants.MaxColumnNameLength)))
+			return
+		}
+		column.Name = name
+	}
+	// The done checkbox comes with a hidden "false" so unticking it is still sent, and edits that
+	// don't mention done leave it alone.
+	if r.Form.Has("done") {
+		column.Done = slices.Contains(r.Form["done"], "true")
+	}
+
+	err = h.storage.EditColumn(ctx, boardName, column)
+	if thatWasAnError(ctx, w, "unable to edit column", err) {
+		return
+	}
 
+	// Whether a column is done changes which cards are blocked anywhere on the board.
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusNoContent)
 }
 
 func (h *Handler) MoveColumn(w http.ResponseWriter, r *http.Request) {
@@ -28,5 +61,5 @@ func (h *Handler) GetColumn(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	components.ColumnComponent(boardName, column).Render(r.Context(), w)
+	components.ColumnComponent(boardName, column, boardViewOptions(r), nil).Render(r.Context(), w)
 }
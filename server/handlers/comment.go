@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/danharasymiw/danban/server/auth"
+	"github.com/danharasymiw/danban/server/constants"
+	"github.com/danharasymiw/danban/server/store"
+	"github.com/danharasymiw/danban/server/ui/components"
+)
+
+func (h *Handler) GetComments(w http.ResponseWriter, r *http.Request) {
+	h.renderComments(w, r)
+}
+
+func (h *Handler) AddComment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	cardId := chi.URLParam(r, "cardId")
+
+	body, err := getFormCommentBody(r)
+	if thatWasAnError(ctx, w, "invalid comment", err) {
+		return
+	}
+
+	// Comments are by accounts rather than board members, since anyone can say they're any member.
+	user := auth.UserFromContext(ctx)
+	if user == nil {
+		thatWasAnError(ctx, w, "no author", store.NewBadRequestError("log in before commenting"))
+		return
+	}
+
+	err = h.storage.AddComment(ctx, &store.Comment{
+		CardId:     cardId,
+		AuthorId:   user.Id,
+		AuthorName: user.Name,
+		Body:       body,
+	})
+	if thatWasAnError(ctx, w, "error adding comment", err) {
+		return
+	}
+
+	h.renderComments(w, r)
+}
+
+func (h *Handler) EditCommentView(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+	columnId := chi.URLParam(r, "columnId")
+
+	comment, err := h.getOwnComment(r)
+	if thatWasAnError(ctx, w, "error getting comment", err) {
+		return
+	}
+
+	components.EditComment(boardName, columnId, comment).Render(ctx, w)
+}
+
+func (h *Handler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	comment, err := h.getOwnComment(r)
+	if thatWasAnError(ctx, w, "error getting comment", err) {
+		return
+	}
+
+	comment.Body, err = getFormCommentBody(r)
+	if thatWasAnError(ctx, w, "invalid comment", err) {
+		return
+	}
+
+	err = h.storage.EditComment(ctx, comment)
+	if thatWasAnError(ctx, w, "error editing comment", err) {
+		return
+	}
+
+	h.renderComments(w, r)
+}
+
+func (h *Handler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	comment, err := h.getOwnComment(r)
+	if thatWasAnError(ctx, w, "error getting comment", err) {
+		return
+	}
+
+	err = h.storage.DeleteComment(ctx, comment.Id)
+	if thatWasAnError(ctx, w, "error deleting comment", err) {
+		return
+	}
+
+	h.renderComments(w, r)
+}
+
+// getOwnComment gets the comment from the URL, making sure it was written by the visitor.
+func (h *Handler) getOwnComment(r *http.Request) (*store.Comment, error) {
+	comment, err := h.storage.GetComment(r.Context(), chi.URLParam(r, "commentId"))
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.CardId != chi.URLParam(r, "cardId") {
+		return nil, store.NewNotFoundError("comment", comment.Id)
+	}
+	if user := auth.UserFromContext(r.Context()); user == nil || comment.AuthorId != user.Id {
+		return nil, store.NewForbiddenError("you can only change your own comments")
+	}
+	return comment, nil
+}
+
+func (h *Handler) renderComments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+	columnId := chi.URLParam(r, "columnId")
+	cardId := chi.URLParam(r, "cardId")
+
+	comments, err := h.storage.GetComments(ctx, cardId)
+	if thatWasAnError(ctx, w, "error getting comments", err) {
+		return
+	}
+
+	components.CardComments(boardName, columnId, cardId, comments, requestRole(r)).Render(ctx, w)
+}
+
+func getFormCommentBody(r *http.Request) (string, error) {
+	body := r.FormValue(`body`)
+	if len(body) == 0 || len(body) > constants.MaxCommentLength {
+		return ``, store.NewBadRequestError(fmt.Sprintf(`comment must be between 1 and %d characters`, constants.MaxCommentLength))
+	}
+	return body, nil
+}
@@ -2,33 +2,63 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 
+	"github.com/danharasymiw/danban/server/blob"
 	"github.com/danharasymiw/danban/server/logger"
+	"github.com/danharasymiw/danban/server/oidc"
+	"github.com/danharasymiw/danban/server/ratelimit"
 	"github.com/danharasymiw/danban/server/store"
 )
 
 type Handler struct {
 	storage store.Storage
+	blobs   blob.Storage
+	oidc    *oidc.Provider // Nil when single sign-on isn't set up
+
+	createLimiter    *ratelimit.Limiter
+	autoCreateBoards bool
+	wordBoardNames   bool
+}
+
+// Options are the parts of a Handler that depend on how the server is set up.
+type Options struct {
+	// OIDC is nil when single sign-on isn't set up.
+	OIDC *oidc.Provider
+	// CreateLimiter limits how many boards each visitor can make, nil doesn't limit them.
+	CreateLimiter *ratelimit.Limiter
+	// AutoCreateBoards makes boards that don't exist yet when they're visited, rather than asking whether to.
+	AutoCreateBoards bool
+	// WordBoardNames makes up names for new boards like brave-otter-42, rather than random letters and numbers.
+	WordBoardNames bool
 }
 
-func NewHandler(storage store.Storage) *Handler {
+func NewHandler(storage store.Storage, blobs blob.Storage, opts Options) *Handler {
 	return &Handler{
-		storage: storage,
+		storage:          storage,
+		blobs:            blobs,
+		oidc:             opts.OIDC,
+		createLimiter:    opts.CreateLimiter,
+		autoCreateBoards: opts.AutoCreateBoards,
+		wordBoardNames:   opts.WordBoardNames,
 	}
 }
 
 func thatWasAnError(ctx context.Context, w http.ResponseWriter, msg string, err error) bool {
 	if err != nil {
 		log := logger.New(ctx)
-		log.WithError(err).Errorf("%s: %w", msg, err)
+		log.WithError(err).Error(msg)
 
 		var badRequest *store.BadRequestError
+		var forbidden *store.ForbiddenError
 		var notFound *store.NotFoundError
 
 		if errors.As(err, &badRequest) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else if errors.As(err, &forbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
 		} else if errors.As(err, &notFound) {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
@@ -38,3 +68,9 @@ func thatWasAnError(ctx context.Context, w http.ResponseWriter, msg string, err
 	}
 	return false
 }
+
+// triggerWarning has htmx raise a showWarning event once the response is swapped in, which pops up a toast on the page.
+func triggerWarning(w http.ResponseWriter, warning string) {
+	trigger, _ := json.Marshal(map[string]string{"showWarning": warning})
+	w.Header().Set("HX-Trigger", string(trigger))
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/danharasymiw/danban/server/boardname"
+)
+
+// nameAttempts is how many names to make up before giving up on finding one that's free, which only happens when
+// nearly every name is taken.
+const nameAttempts = 5
+
+// HandleHome sends visitors to a made up board name nobody has used yet.
+func (h *Handler) HandleHome(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	boardName, err := h.freeBoardName(ctx)
+	if thatWasAnError(ctx, w, "error making up board name", err) {
+		return
+	}
+
+	http.Redirect(w, r, "/board/"+boardName, http.StatusFound)
+}
+
+// GoToBoard sends the search bar to the board it names.
+func (h *Handler) GoToBoard(w http.ResponseWriter, r *http.Request) {
+	boardName := strings.TrimSpace(r.URL.Query().Get("name"))
+	if err := boardname.Check(boardName); err != nil {
+		if r.Header.Get("HX-Request") == "true" {
+			// Leave the page alone and pop up what was wrong, since the search bar swaps the whole body.
+			w.Header().Set("HX-Reswap", "none")
+			triggerWarning(w, err.Error())
+			return
+		}
+		thatWasAnError(r.Context(), w, "invalid board name", err)
+		return
+	}
+
+	http.Redirect(w, r, "/board/"+url.PathEscape(boardName), http.StatusFound)
+}
+
+func (h *Handler) freeBoardName(ctx context.Context) (string, error) {
+	for range nameAttempts {
+		boardName := boardname.Random()
+		if h.wordBoardNames {
+			boardName = boardname.Words()
+		}
+
+		exists, err := h.boardExists(ctx, boardName)
+		if err != nil {
+			return ``, err
+		}
+		if !exists {
+			return boardName, nil
+		}
+	}
+	return ``, fmt.Errorf("no free board name after %d attempts", nameAttempts)
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/danharasymiw/danban/server/store"
+)
+
+// boardsStorage only knows which boards exist, which is all picking a name for a new board needs.
+type boardsStorage struct {
+	store.Storage
+	boards map[string]bool
+}
+
+func (s *boardsStorage) GetBoard(ctx context.Context, boardName string, filter *store.CardFilter) (*store.Board, error) {
+	if !s.boards[boardName] {
+		return nil, store.NewNotFoundError("board", boardName)
+	}
+	return &store.Board{Name: boardName}, nil
+}
+
+func TestHandleHomeNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		pattern string
+	}{
+		{name: "random", opts: Options{}, pattern: `^/board/[A-Za-z0-9]{12}$`},
+		{name: "words", opts: Options{WordBoardNames: true}, pattern: `^/board/[a-z]+-[a-z]+-[0-9]{2,3}$`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := NewHandler(&boardsStorage{}, nil, test.opts)
+
+			w := httptest.NewRecorder()
+			h.HandleHome(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if w.Code != http.StatusFound {
+				t.Fatalf("got status %d, want %d", w.Code, http.StatusFound)
+			}
+			if location := w.Header().Get("Location"); !regexp.MustCompile(test.pattern).MatchString(location) {
+				t.Errorf("redirected to %q, want a match for %s", location, test.pattern)
+			}
+		})
+	}
+}
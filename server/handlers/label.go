@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/danharasymiw/danban/server/constants"
+	"github.com/danharasymiw/danban/server/store"
+	"github.com/danharasymiw/danban/server/ui/components"
+)
+
+func (h *Handler) AddLabel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+
+	label, err := getFormLabel(r)
+	if thatWasAnError(ctx, w, "invalid label", err) {
+		return
+	}
+
+	err = h.storage.AddLabel(ctx, boardName, label)
+	if thatWasAnError(ctx, w, "error adding label", err) {
+		return
+	}
+
+	// A label created from the edit modal is most likely meant for the card being edited.
+	components.LabelOption(boardName, label, true).Render(ctx, w)
+}
+
+func (h *Handler) DeleteLabel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+	labelId := chi.URLParam(r, "labelId")
+
+	err := h.storage.DeleteLabel(ctx, boardName, labelId)
+	if thatWasAnError(ctx, w, "error deleting label", err) {
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func getFormLabel(r *http.Request) (*store.Label, error) {
+	name := r.FormValue(`labelName`)
+	if len(name) == 0 || len(name) > constants.MaxLabelNameLength {
+		return nil, store.NewBadRequestError(fmt.Sprintf(`label name must be between 1 and %d characters`, constants.MaxLabelNameLength))
+	}
+
+	color := r.FormValue(`labelColor`)
+	if !slices.Contains(constants.LabelColors, color) {
+		return nil, store.NewBadRequestError(fmt.Sprintf(`unknown label color: %s`, color))
+	}
+
+	return &store.Label{
+		Name:  name,
+		Color: color,
+	}, nil
+}
+
+// getFormCardLabels returns the board labels that were checked in the edit card form.
+func getFormCardLabels(r *http.Request, palette []*store.Label) ([]*store.Label, error) {
+	err := r.ParseForm()
+	if err != nil {
+		return nil, store.NewBadRequestError("invalid form")
+	}
+
+	labels := make([]*store.Label, 0, len(r.Form[`labelIds`]))
+	for _, labelId := range r.Form[`labelIds`] {
+		idx := slices.IndexFunc(palette, func(l *store.Label) bool { return l.Id == labelId })
+		if idx < 0 {
+			return nil, store.NewBadRequestError(fmt.Sprintf(`unknown label: %s`, labelId))
+		}
+		labels = append(labels, palette[idx])
+	}
+	return labels, nil
+}
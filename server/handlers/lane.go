@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/danharasymiw/danban/server/constants"
+	"github.com/danharasymiw/danban/server/store"
+	"github.com/danharasymiw/danban/server/ui/components"
+)
+
+// laneDropIndex works out where in its new column a card dropped into a lane goes. A lane only shows some of the
+// column's cards, so the index comes from the cards it was dropped next to rather than its position in the lane.
+func (h *Handler) laneDropIndex(ctx context.Context, card *store.Card, req moveCardRequest) (int, error) {
+	neighbourId, after := req.BeforeCardId, false
+	if neighbourId == `` {
+		neighbourId, after = req.AfterCardId, true
+	}
+	// Dropped into an empty lane, it may as well go at the bottom of the column.
+	if neighbourId == `` {
+		return -1, nil
+	}
+
+	neighbour, err := h.storage.GetCard(ctx, neighbourId)
+	if err != nil {
+		return 0, err
+	}
+	if neighbour.ColumnId != req.ToColumnId {
+		return 0, store.NewBadRequestError("the card was dropped next to a card in another column")
+	}
+
+	index := neighbour.Index
+	if after {
+		index++
+	}
+	// Taking the card out of its column first moves everything below it up by one.
+	if card.ColumnId == req.ToColumnId && card.Index < index {
+		index--
+	}
+	return index, nil
+}
+
+// putCardInLane changes whatever the board's lanes are split by on the card, so it shows up in the lane it was
+// dragged to. Only the label or assignee the card was in the lane for is swapped, any others are kept. The card is
+// only changed in memory, so nothing is saved until the whole move is known to be fine, and it reports whether the
+// card needs saving.
+func (h *Handler) putCardInLane(ctx context.Context, boardName string, card *store.Card, req moveCardRequest) (bool, error) {
+	if !slices.Contains([]string{components.LanesByLabel, components.LanesByAssignee, components.LanesByLane}, req.Lanes) {
+		return false, store.NewBadRequestError(fmt.Sprintf("unknown lanes: %s", req.Lanes))
+	}
+	if req.FromLane == req.ToLane {
+		return false, nil
+	}
+
+	switch req.Lanes {
+	case components.LanesByLabel:
+		labels, err := h.storage.GetLabels(ctx, boardName)
+		if err != nil {
+			return false, err
+		}
+		card.Labels = slices.DeleteFunc(card.Labels, func(l *store.Label) bool {
+			return l.Id == req.FromLane || l.Id == req.ToLane
+		})
+		if req.ToLane != `` {
+			i := slices.IndexFunc(labels, func(l *store.Label) bool { return l.Id == req.ToLane })
+			if i < 0 {
+				return false, store.NewNotFoundError("label", req.ToLane)
+			}
+			// The first label decides the card's lane.
+			card.Labels = slices.Insert(card.Labels, 0, labels[i])
+		}
+	case components.LanesByAssignee:
+		members, err := h.storage.GetMembers(ctx, boardName)
+		if err != nil {
+			return false, err
+		}
+		card.Assignees = slices.DeleteFunc(card.Assignees, func(m *store.Member) bool {
+			return m.Id == req.FromLane || m.Id == req.ToLane
+		})
+		if req.ToLane != `` {
+			i := slices.IndexFunc(members, func(m *store.Member) bool { return m.Id == req.ToLane })
+			if i < 0 {
+				return false, store.NewNotFoundError("member", req.ToLane)
+			}
+			// The first assignee decides the card's lane.
+			card.Assignees = slices.Insert(card.Assignees, 0, members[i])
+		}
+	case components.LanesByLane:
+		if len(req.ToLane) > constants.MaxLaneNameLength {
+			return false, store.NewBadRequestError(fmt.Sprintf(`lane cannot exceed %d characters`, constants.MaxLaneNameLength))
+		}
+		card.Lane = req.ToLane
+	}
+	return true, nil
+}
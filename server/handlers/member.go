@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/danharasymiw/danban/server/constants"
+	"github.com/danharasymiw/danban/server/store"
+	"github.com/danharasymiw/danban/server/ui/components"
+)
+
+// meCookieName holds the member a visitor picked as themselves, scoped to the board's path.
+const meCookieName = "danban-me"
+
+func (h *Handler) AddMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+
+	name := r.FormValue(`memberName`)
+	if len(name) == 0 || len(name) > constants.MaxMemberNameLength {
+		thatWasAnError(ctx, w, "invalid member name", store.NewBadRequestError(fmt.Sprintf(`member name must be between 1 and %d characters`, constants.MaxMemberNameLength)))
+		return
+	}
+
+	member := &store.Member{Name: name}
+	err := h.storage.AddMember(ctx, boardName, member)
+	if thatWasAnError(ctx, w, "error adding member", err) {
+		return
+	}
+
+	components.AssigneeOption(boardName, member, true).Render(ctx, w)
+}
+
+func (h *Handler) DeleteMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+	memberId := chi.URLParam(r, "memberId")
+
+	err := h.storage.DeleteMember(ctx, boardName, memberId)
+	if thatWasAnError(ctx, w, "error deleting member", err) {
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// SetMe remembers which board member the visitor is, so "my cards" means something.
+func (h *Handler) SetMe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+	memberId := r.FormValue(`memberId`)
+
+	cookie := &http.Cookie{
+		Name:     meCookieName,
+		Value:    memberId,
+		Path:     fmt.Sprintf("/board/%s", boardName),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+
+	if memberId == `` {
+		cookie.MaxAge = -1
+	} else {
+		members, err := h.storage.GetMembers(ctx, boardName)
+		if thatWasAnError(ctx, w, "error getting board members", err) {
+			return
+		}
+		if !slices.ContainsFunc(members, func(m *store.Member) bool { return m.Id == memberId }) {
+			thatWasAnError(ctx, w, "unknown member", store.NewBadRequestError(fmt.Sprintf(`unknown member: %s`, memberId)))
+			return
+		}
+		cookie.MaxAge = 60 * 60 * 24 * 365
+	}
+
+	http.SetCookie(w, cookie)
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func currentMemberId(r *http.Request) string {
+	cookie, err := r.Cookie(meCookieName)
+	if err != nil {
+		return ``
+	}
+	return cookie.Value
+}
+
+// getFormCardAssignees returns the board members that were checked in the edit card form.
+func getFormCardAssignees(r *http.Request, members []*store.Member) ([]*store.Member, error) {
+	err := r.ParseForm()
+	if err != nil {
+		return nil, store.NewBadRequestError("invalid form")
+	}
+
+	assignees := make([]*store.Member, 0, len(r.Form[`assigneeIds`]))
+	for _, memberId := range r.Form[`assigneeIds`] {
+		idx := slices.IndexFunc(members, func(m *store.Member) bool { return m.Id == memberId })
+		if idx < 0 {
+			return nil, store.NewBadRequestError(fmt.Sprintf(`unknown member: %s`, memberId))
+		}
+		assignees = append(assignees, members[idx])
+	}
+	return assignees, nil
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/danharasymiw/danban/server/auth"
+	"github.com/danharasymiw/danban/server/boardname"
+	"github.com/danharasymiw/danban/server/constants"
+	"github.com/danharasymiw/danban/server/store"
+	"github.com/danharasymiw/danban/server/ui/components"
+	"github.com/danharasymiw/danban/server/ui/views"
+)
+
+// NewBoardView shows the form for making a board, with the name from the query filled in.
+func (h *Handler) NewBoardView(w http.ResponseWriter, r *http.Request) {
+	views.NewBoard(newBoardForm(r, r.URL.Query().Get("name")), ``, false).Render(r.Context(), w)
+}
+
+// CreateBoard makes a board from the new board form and shows it.
+func (h *Handler) CreateBoard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	form := views.NewBoardForm{
+		Name:       strings.TrimSpace(r.FormValue(`name`)),
+		Template:   r.FormValue(`template`),
+		Visibility: r.FormValue(`visibility`),
+	}
+	err := h.checkNewBoard(ctx, form)
+	var badRequest *store.BadRequestError
+	if errors.As(err, &badRequest) {
+		// Show the form again with what was wrong, rather than an error page.
+		views.NewBoard(form, err.Error(), false).Render(ctx, w)
+		return
+	}
+	if thatWasAnError(ctx, w, "error checking new board", err) {
+		return
+	}
+
+	if !h.createLimiter.Allow(w, r) {
+		return
+	}
+
+	opts := components.BoardViewOptions{
+		Me:   currentMemberId(r),
+		Role: store.RoleEditor,
+	}
+	if form.Visibility == constants.BoardVisibilityPrivate {
+		opts.Role = store.RoleOwner
+	}
+	_, opts.PasswordSetupToken, err = h.createNewBoard(ctx, form.Name, form.Template, form.Visibility)
+	if errors.As(err, &badRequest) {
+		// Most likely someone else took the name since it was checked.
+		views.NewBoard(form, err.Error(), false).Render(ctx, w)
+		return
+	}
+	if thatWasAnError(ctx, w, "failed to create board", err) {
+		return
+	}
+	if opts.PasswordSetupToken != `` {
+		auth.RememberBoardCreator(w, r, form.Name, opts.PasswordSetupToken)
+	}
+
+	// The board is shown straight away rather than redirected to, since the password setup token is only shown once.
+	w.Header().Set("HX-Push-Url", "/board/"+form.Name)
+	h.renderBoard(w, r, form.Name, opts, nil)
+}
+
+// newBoardForm is how the new board form starts out, private when there's someone logged in to own the board.
+func newBoardForm(r *http.Request, boardName string) views.NewBoardForm {
+	visibility := constants.BoardVisibilityOpen
+	if auth.UserFromContext(r.Context()) != nil {
+		visibility = constants.BoardVisibilityPrivate
+	}
+	return views.NewBoardForm{
+		Name:       boardName,
+		Template:   constants.DefaultBoardTemplate,
+		Visibility: visibility,
+	}
+}
+
+func (h *Handler) checkNewBoard(ctx context.Context, form views.NewBoardForm) error {
+	if err := boardname.Check(form.Name); err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(constants.BoardTemplates, func(t constants.BoardTemplate) bool { return t.Name == form.Template }) {
+		return store.NewBadRequestError(`pick a template for the board`)
+	}
+
+	switch form.Visibility {
+	case constants.BoardVisibilityOpen:
+	case constants.BoardVisibilityPrivate:
+		if auth.UserFromContext(ctx) == nil {
+			return store.NewBadRequestError(`log in to make a private board`)
+		}
+	default:
+		return store.NewBadRequestError(`pick who can see the board`)
+	}
+
+	exists, err := h.boardExists(ctx, form.Name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return store.NewBadRequestError(fmt.Sprintf(`there's already a board called %s`, form.Name))
+	}
+	return nil
+}
+
+func (h *Handler) boardExists(ctx context.Context, boardName string) (bool, error) {
+	_, err := h.storage.GetBoard(ctx, boardName, nil)
+	if _, ok := err.(*store.NotFoundError); ok {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// createNewBoard makes the board with the template's columns. Private boards are owned by whoever made them, open
+// ones are left for anyone to edit and come with a token for locking them with a password or taking ownership of
+// them later, see auth.RememberBoardCreator.
+func (h *Handler) createNewBoard(ctx context.Context, boardName, templateName, visibility string) (*store.Board, string, error) {
+	var roles []*store.BoardRole
+	var setupToken, setupHash string
+	if user := auth.UserFromContext(ctx); user != nil && visibility == constants.BoardVisibilityPrivate {
+		roles = []*store.BoardRole{{UserId: user.Id, Role: store.RoleOwner}}
+	} else {
+		setupToken = auth.NewToken()
+		setupHash = auth.HashToken(setupToken)
+	}
+
+	i := slices.IndexFunc(constants.BoardTemplates, func(t constants.BoardTemplate) bool { return t.Name == templateName })
+	if i < 0 {
+		return nil, ``, store.NewBadRequestError(fmt.Sprintf(`there's no board template called %s`, templateName))
+	}
+	template := constants.BoardTemplates[i]
+
+	var columns []*store.Column
+	for index, name := range template.Columns {
+		columns = append(columns, &store.Column{
+			Index: index,
+			Name:  name,
+			Done:  index == len(template.Columns)-1,
+			Cards: []*store.Card{},
+		})
+	}
+	columns[0].Cards = []*store.Card{
+		{
+			Id:          "id",
+			Index:       0,
+			Title:       "Make cards",
+			Description: "This is a new board, make some cards!",
+		},
+	}
+
+	board := &store.Board{
+		Name:              boardName,
+		Columns:           columns,
+		Roles:             roles,
+		PasswordSetupHash: setupHash,
+		ClaimHash:         setupHash,
+	}
+	return board, setupToken, h.storage.AddBoard(ctx, board)
+}
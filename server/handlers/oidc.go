@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/danharasymiw/danban/server/auth"
+	"github.com/danharasymiw/danban/server/constants"
+	"github.com/danharasymiw/danban/server/logger"
+	"github.com/danharasymiw/danban/server/oidc"
+	"github.com/danharasymiw/danban/server/store"
+	"github.com/danharasymiw/danban/server/ui/views"
+)
+
+// oidcCookieName holds what's needed to finish a single sign-on login once the provider sends the user back.
+const oidcCookieName = "danban-oidc"
+
+// OIDCLogin sends the user off to the single sign-on provider to log in.
+func (h *Handler) OIDCLogin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.oidc == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	login := url.Values{
+		"state":    {oidc.RandomString()},
+		"nonce":    {oidc.RandomString()},
+		"verifier": {oidc.RandomString()},
+		"next":     {r.URL.Query().Get("next")},
+	}
+
+	authURL, err := h.oidc.AuthCodeURL(ctx, h.oidc.RedirectURL(r), login.Get("state"), login.Get("nonce"), login.Get("verifier"))
+	if thatWasAnError(ctx, w, "error building oidc login url", err) {
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcCookieName,
+		Value:    login.Encode(),
+		Path:     "/login/oidc",
+		MaxAge:   10 * 60,
+		HttpOnly: true,
+		Secure:   auth.IsSecure(r),
+		// The provider sends the user back with a top level navigation, which lax cookies are sent with.
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// OIDCCallback finishes a single sign-on login, logging in the user the provider's claims belong to.
+func (h *Handler) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.oidc == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	var login url.Values
+	if cookie, err := r.Cookie(oidcCookieName); err == nil {
+		login, _ = url.ParseQuery(cookie.Value)
+	}
+	http.SetCookie(w, &http.Cookie{Name: oidcCookieName, Path: "/login/oidc", MaxAge: -1})
+
+	query := r.URL.Query()
+	if problem := query.Get("error"); problem != `` {
+		views.Login("single sign-on failed: "+problem, login.Get("next"), true).Render(ctx, w)
+		return
+	}
+	if login.Get("state") == `` || query.Get("state") != login.Get("state") {
+		views.Login("single sign-on took too long or was started elsewhere, try again", login.Get("next"), true).Render(ctx, w)
+		return
+	}
+
+	claims, err := h.oidc.Exchange(ctx, h.oidc.RedirectURL(r), query.Get("code"), login.Get("verifier"), login.Get("nonce"))
+	if err != nil {
+		logger.New(ctx).WithError(err).Error("Failed to exchange oidc code")
+		views.Login("single sign-on failed, try again", login.Get("next"), true).Render(ctx, w)
+		return
+	}
+
+	user, err := h.oidcUser(ctx, claims)
+	var badRequest *store.BadRequestError
+	if errors.As(err, &badRequest) {
+		views.Login(err.Error(), login.Get("next"), true).Render(ctx, w)
+		return
+	}
+	if thatWasAnError(ctx, w, "error getting oidc user", err) {
+		return
+	}
+
+	err = auth.StartSession(ctx, w, r, h.storage, user)
+	if thatWasAnError(ctx, w, "error starting session", err) {
+		return
+	}
+
+	http.Redirect(w, r, safeRedirect(login.Get("next")), http.StatusSeeOther)
+}
+
+// oidcUser finds the user the claims are about. Someone who already registered with the same verified email has
+// their account linked, otherwise a new account is made for them.
+func (h *Handler) oidcUser(ctx context.Context, claims *oidc.Claims) (*store.User, error) {
+	var notFound *store.NotFoundError
+
+	user, err := h.storage.GetUserByOIDCSubject(ctx, claims.Issuer, claims.Subject)
+	if !errors.As(err, &notFound) {
+		return user, err
+	}
+
+	email := normalizeEmail(claims.Email)
+	if email == `` {
+		return nil, store.NewBadRequestError("your single sign-on account needs an email address to log in here")
+	}
+
+	if claims.EmailVerified {
+		user, err = h.storage.GetUserByEmail(ctx, email)
+		if err == nil {
+			err = h.storage.LinkUserOIDC(ctx, user.Id, claims.Issuer, claims.Subject)
+			return user, err
+		}
+		if !errors.As(err, &notFound) {
+			return nil, err
+		}
+	}
+
+	user = &store.User{
+		Email:       email,
+		Name:        oidcName(claims),
+		CreatedAt:   time.Now(),
+		OIDCIssuer:  claims.Issuer,
+		OIDCSubject: claims.Subject,
+	}
+	err = h.storage.AddUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func oidcName(claims *oidc.Claims) string {
+	name := strings.TrimSpace(claims.Name)
+	if name == `` {
+		name = strings.TrimSpace(claims.PreferredUsername)
+	}
+	if name == `` {
+		name, _, _ = strings.Cut(claims.Email, "@")
+	}
+	if len(name) > constants.MaxMemberNameLength {
+		name = name[:constants.MaxMemberNameLength]
+	}
+	return name
+}
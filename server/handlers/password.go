@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/danharasymiw/danban/server/auth"
+	"github.com/danharasymiw/danban/server/constants"
+	"github.com/danharasymiw/danban/server/store"
+	"github.com/danharasymiw/danban/server/ui/views"
+)
+
+// SetBoardPassword locks a board nobody owns with a password. Only whoever made the board gets the setup token
+// needed to do it, and only until they've used it.
+func (h *Handler) SetBoardPassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+
+	setupToken := r.FormValue(`setupToken`)
+	if setupToken == `` {
+		thatWasAnError(ctx, w, "no board password setup token", store.NewBadRequestError("only the board's creator can set its password"))
+		return
+	}
+
+	password := r.FormValue(`password`)
+	if len(password) < constants.MinPasswordLength || len(password) > constants.MaxPasswordLength {
+		thatWasAnError(ctx, w, "invalid board password", store.NewBadRequestError(fmt.Sprintf(`password must be between %d and %d characters`, constants.MinPasswordLength, constants.MaxPasswordLength)))
+		return
+	}
+
+	passwordHash, err := auth.HashPassword(password)
+	if thatWasAnError(ctx, w, "error hashing board password", err) {
+		return
+	}
+
+	err = h.storage.SetBoardPassword(ctx, boardName, auth.HashToken(setupToken), passwordHash)
+	if thatWasAnError(ctx, w, "error setting board password", err) {
+		return
+	}
+
+	// Whoever set the password shouldn't have to enter it straight away.
+	auth.UnlockBoard(w, r, boardName, passwordHash)
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UnlockBoard lets the visitor into a password protected board once they've entered its password.
+func (h *Handler) UnlockBoard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+	next := r.FormValue(`next`)
+
+	passwordHash, err := h.storage.GetBoardPasswordHash(ctx, boardName)
+	if thatWasAnError(ctx, w, "error getting board password", err) {
+		return
+	}
+
+	if passwordHash != `` {
+		if !auth.CheckBoardPassword(passwordHash, r.FormValue(`password`)) {
+			views.BoardPassword(boardName, "wrong password", next).Render(ctx, w)
+			return
+		}
+		auth.UnlockBoard(w, r, boardName, passwordHash)
+	}
+
+	if next == `` {
+		next = fmt.Sprintf("/board/%s", boardName)
+	}
+	http.Redirect(w, r, safeRedirect(next), http.StatusSeeOther)
+}
+
+// askForBoardPassword asks the visitor for the board's password before going any further, the same way forbidden
+// explains why they can't.
+func askForBoardPassword(w http.ResponseWriter, r *http.Request, boardName, problem string) {
+	if !wantsPage(r) {
+		http.Error(w, "enter the board's password first", http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusForbidden)
+	views.BoardPassword(boardName, problem, r.URL.RequestURI()).Render(r.Context(), w)
+}
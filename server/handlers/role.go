@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/danharasymiw/danban/server/auth"
+	"github.com/danharasymiw/danban/server/logger"
+	"github.com/danharasymiw/danban/server/store"
+	"github.com/danharasymiw/danban/server/ui/views"
+)
+
+// HandleRoles shows who has which role on the board.
+func (h *Handler) HandleRoles(w http.ResponseWriter, r *http.Request) {
+	h.renderRoles(w, r, ``, ``)
+}
+
+// AddBoardRole gives the user with the form's email a role on the board.
+func (h *Handler) AddBoardRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	role, err := getFormRole(r)
+	if err == nil {
+		var user *store.User
+		user, err = h.storage.GetUserByEmail(ctx, normalizeEmail(r.FormValue(`email`)))
+		var notFound *store.NotFoundError
+		if errors.As(err, &notFound) {
+			err = store.NewBadRequestError("nobody has an account with that email yet, ask them to register first")
+		}
+		if err == nil {
+			err = h.setBoardRole(r, &store.BoardRole{UserId: user.Id, Role: role})
+		}
+	}
+	h.renderRolesOrProblem(w, r, "error adding board role", err)
+}
+
+// EditBoardRole changes the role a user has on the board.
+func (h *Handler) EditBoardRole(w http.ResponseWriter, r *http.Request) {
+	role, err := getFormRole(r)
+	if err == nil {
+		err = h.setBoardRole(r, &store.BoardRole{UserId: chi.URLParam(r, "userId"), Role: role})
+	}
+	h.renderRolesOrProblem(w, r, "error editing board role", err)
+}
+
+// DeleteBoardRole takes a user off the board.
+func (h *Handler) DeleteBoardRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+	userId := chi.URLParam(r, "userId")
+
+	roles, err := h.storage.GetBoardRoles(ctx, boardName)
+	if err == nil {
+		roles = slices.DeleteFunc(roles, func(role *store.BoardRole) bool { return role.UserId == userId })
+		err = needsAnOwner(roles)
+	}
+	if err == nil {
+		err = h.storage.DeleteBoardRole(ctx, boardName, userId)
+	}
+	h.renderRolesOrProblem(w, r, "error deleting board role", err)
+}
+
+// ClaimBoard makes the visitor the owner of a board nobody owns yet, which makes the board private to the people
+// they add to it. Only whoever made the board can, using the token it was made with.
+func (h *Handler) ClaimBoard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+
+	user := auth.UserFromContext(ctx)
+	if user == nil {
+		forbidden(w, r, "log in to take ownership of a board")
+		return
+	}
+
+	claimToken := auth.BoardCreatorToken(r)
+	if claimToken == `` {
+		forbidden(w, r, "only whoever made the board can take ownership of it")
+		return
+	}
+
+	roles, err := h.storage.GetBoardRoles(ctx, boardName)
+	if err == nil && len(roles) > 0 {
+		err = store.NewBadRequestError("this board already has an owner")
+	}
+	if err == nil {
+		err = h.storage.ClaimBoard(ctx, boardName, auth.HashToken(claimToken), user.Id)
+	}
+	h.renderRolesOrProblem(w, r, "error claiming board", err)
+}
+
+// setBoardRole gives a user a role on the URL's board, as long as that leaves the board with an owner.
+func (h *Handler) setBoardRole(r *http.Request, role *store.BoardRole) error {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+
+	roles, err := h.storage.GetBoardRoles(ctx, boardName)
+	if err != nil {
+		return err
+	}
+
+	roles = slices.DeleteFunc(roles, func(other *store.BoardRole) bool { return other.UserId == role.UserId })
+	err = needsAnOwner(append(roles, role))
+	if err != nil {
+		return err
+	}
+
+	return h.storage.SetBoardRole(ctx, boardName, role)
+}
+
+// needsAnOwner makes sure a board that has roles is left with someone who can change them.
+func needsAnOwner(roles []*store.BoardRole) error {
+	if !slices.ContainsFunc(roles, func(role *store.BoardRole) bool { return role.Role == store.RoleOwner }) {
+		return store.NewBadRequestError("the board needs at least one owner")
+	}
+	return nil
+}
+
+// renderRolesOrProblem shows the board's roles, along with what was wrong with the change when it was a bad request.
+func (h *Handler) renderRolesOrProblem(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	var badRequest *store.BadRequestError
+	if errors.As(err, &badRequest) {
+		h.renderRoles(w, r, err.Error(), ``)
+		return
+	}
+	if thatWasAnError(r.Context(), w, msg, err) {
+		return
+	}
+	h.renderRoles(w, r, ``, ``)
+}
+
+// renderRoles shows the board's members page. A share link that was just made is shown along with it, since this is
+// the only time it can be.
+func (h *Handler) renderRoles(w http.ResponseWriter, r *http.Request, problem, newShareLink string) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+
+	roles, err := h.storage.GetBoardRoles(ctx, boardName)
+	if thatWasAnError(ctx, w, "error getting board roles", err) {
+		return
+	}
+
+	users := make([]views.BoardUser, 0, len(roles))
+	for _, role := range roles {
+		user, err := h.storage.GetUser(ctx, role.UserId)
+		var notFound *store.NotFoundError
+		if errors.As(err, &notFound) {
+			logger.New(ctx).WithField("user id", role.UserId).Warn("Board role belongs to a missing user")
+			continue
+		}
+		if thatWasAnError(ctx, w, "error getting board user", err) {
+			return
+		}
+		users = append(users, views.BoardUser{User: user, Role: role.Role})
+	}
+
+	// The visitor's role is worked out again, as they may have just changed it.
+	role := roleFor(roles, auth.UserFromContext(ctx))
+
+	var links []*store.ShareLink
+	if role == store.RoleOwner {
+		links, err = h.storage.GetShareLinks(ctx, boardName)
+		if thatWasAnError(ctx, w, "error getting share links", err) {
+			return
+		}
+	}
+
+	canClaim := len(roles) == 0 && auth.BoardCreatorToken(r) != ``
+	views.Roles(boardName, users, links, role, canClaim, problem, newShareLink).Render(ctx, w)
+}
+
+func getFormRole(r *http.Request) (store.Role, error) {
+	role := store.Role(r.FormValue(`role`))
+	if !slices.Contains(store.Roles, role) {
+		return ``, store.NewBadRequestError("pick a role")
+	}
+	return role, nil
+}
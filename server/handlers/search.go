@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/danharasymiw/danban/server/constants"
+	"github.com/danharasymiw/danban/server/ui/components"
+)
+
+func (h *Handler) SearchCards(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+	query := strings.TrimSpace(r.FormValue(`q`))
+
+	// Too short to be worth searching for, clear the results until there's more to go on.
+	runes := []rune(query)
+	if len(runes) < constants.MinSearchLength {
+		components.SearchResults(boardName, query, nil).Render(ctx, w)
+		return
+	}
+	// Cut by characters, cutting by bytes could leave half of a multi-byte character on the end.
+	if len(runes) > constants.MaxSearchLength {
+		query = string(runes[:constants.MaxSearchLength])
+	}
+
+	cards, err := h.storage.SearchCards(ctx, boardName, query, constants.MaxSearchResults)
+	if thatWasAnError(ctx, w, "error searching cards", err) {
+		return
+	}
+
+	components.SearchResults(boardName, query, cards).Render(ctx, w)
+}
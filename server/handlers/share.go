@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/danharasymiw/danban/server/auth"
+	"github.com/danharasymiw/danban/server/constants"
+	"github.com/danharasymiw/danban/server/store"
+)
+
+// shareCookieName holds the share link a visitor opened, scoped to the board's pages.
+const shareCookieName = "danban-share"
+
+// OpenShareLink lets whoever has the link look at the board, remembering the link for the rest of the board's pages.
+func (h *Handler) OpenShareLink(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+	token := chi.URLParam(r, "token")
+
+	ok, err := h.validShareToken(ctx, boardName, token)
+	if thatWasAnError(ctx, w, "error checking share link", err) {
+		return
+	}
+	if !ok {
+		forbidden(w, r, "this share link has been revoked")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     shareCookieName,
+		Value:    token,
+		Path:     fmt.Sprintf("/board/%s", boardName),
+		HttpOnly: true,
+		Secure:   auth.IsSecure(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, fmt.Sprintf("/board/%s", boardName), http.StatusFound)
+}
+
+// AddShareLink makes a new link to the board, which is only shown this once since just its hash is kept.
+func (h *Handler) AddShareLink(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+
+	name, err := getFormShareLinkName(r)
+	if err != nil {
+		h.renderRolesOrProblem(w, r, "invalid share link", err)
+		return
+	}
+
+	links, err := h.storage.GetShareLinks(ctx, boardName)
+	if thatWasAnError(ctx, w, "error getting share links", err) {
+		return
+	}
+	if len(links) >= constants.MaxShareLinks {
+		h.renderRolesOrProblem(w, r, "too many share links", store.NewBadRequestError(fmt.Sprintf("boards can only have %d share links, revoke one first", constants.MaxShareLinks)))
+		return
+	}
+
+	token := auth.NewToken()
+	err = h.storage.AddShareLink(ctx, boardName, &store.ShareLink{
+		Name:      name,
+		TokenHash: auth.HashToken(token),
+		CreatedAt: time.Now(),
+	})
+	if thatWasAnError(ctx, w, "error adding share link", err) {
+		return
+	}
+
+	scheme := "http"
+	if auth.IsSecure(r) {
+		scheme = "https"
+	}
+	h.renderRoles(w, r, ``, fmt.Sprintf("%s://%s/board/%s/shared/%s", scheme, r.Host, boardName, token))
+}
+
+// DeleteShareLink revokes the link, anyone who opened it can't see the board anymore.
+func (h *Handler) DeleteShareLink(w http.ResponseWriter, r *http.Request) {
+	err := h.storage.DeleteShareLink(r.Context(), chi.URLParam(r, "boardName"), chi.URLParam(r, "linkId"))
+	h.renderRolesOrProblem(w, r, "error deleting share link", err)
+}
+
+// shareRole is the role the share link the visitor opened gives them on the board, if it hasn't been revoked.
+func (h *Handler) shareRole(r *http.Request, boardName string) (store.Role, error) {
+	cookie, err := r.Cookie(shareCookieName)
+	if err != nil {
+		return ``, nil
+	}
+
+	ok, err := h.validShareToken(r.Context(), boardName, cookie.Value)
+	if err != nil || !ok {
+		return ``, err
+	}
+	// Share links only ever let people look, whatever they try to do with them.
+	return store.RoleViewer, nil
+}
+
+func (h *Handler) validShareToken(ctx context.Context, boardName, token string) (bool, error) {
+	links, err := h.storage.GetShareLinks(ctx, boardName)
+	if err != nil {
+		return false, err
+	}
+
+	hash := auth.HashToken(token)
+	return slices.ContainsFunc(links, func(link *store.ShareLink) bool { return link.TokenHash == hash }), nil
+}
+
+func getFormShareLinkName(r *http.Request) (string, error) {
+	name := strings.TrimSpace(r.FormValue(`name`))
+	if len(name) == 0 || len(name) > constants.MaxShareLinkNameLength {
+		return ``, store.NewBadRequestError(fmt.Sprintf(`share link name must be between 1 and %d characters`, constants.MaxShareLinkNameLength))
+	}
+	return name, nil
+}
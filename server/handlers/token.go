@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/danharasymiw/danban/server/auth"
+	"github.com/danharasymiw/danban/server/constants"
+	"github.com/danharasymiw/danban/server/store"
+	"github.com/danharasymiw/danban/server/ui/views"
+)
+
+// APITokensView lists the logged in user's API tokens.
+func (h *Handler) APITokensView(w http.ResponseWriter, r *http.Request) {
+	if requireUser(w, r) == nil {
+		return
+	}
+	h.renderAPITokens(w, r, ``, ``)
+}
+
+// AddAPIToken makes the user a new API token, which is only shown this once since just its hash is kept.
+func (h *Handler) AddAPIToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	token, err := getFormAPIToken(r)
+	if err == nil {
+		var tokens []*store.APIToken
+		tokens, err = h.storage.GetAPITokens(ctx, user.Id)
+		if err == nil && len(tokens) >= constants.MaxAPITokens {
+			err = store.NewBadRequestError(fmt.Sprintf("you can only have %d API tokens, revoke one first", constants.MaxAPITokens))
+		}
+	}
+
+	var badRequest *store.BadRequestError
+	if errors.As(err, &badRequest) {
+		h.renderAPITokens(w, r, err.Error(), ``)
+		return
+	}
+	if thatWasAnError(ctx, w, "error checking api token", err) {
+		return
+	}
+
+	secret := auth.NewAPIToken()
+	token.UserId = user.Id
+	token.Hash = auth.HashToken(secret)
+	err = h.storage.AddAPIToken(ctx, token)
+	if thatWasAnError(ctx, w, "error adding api token", err) {
+		return
+	}
+
+	h.renderAPITokens(w, r, ``, secret)
+}
+
+// DeleteAPIToken revokes one of the user's API tokens.
+func (h *Handler) DeleteAPIToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	err := h.storage.DeleteAPIToken(ctx, user.Id, chi.URLParam(r, "tokenId"))
+	if thatWasAnError(ctx, w, "error deleting api token", err) {
+		return
+	}
+
+	h.renderAPITokens(w, r, ``, ``)
+}
+
+func (h *Handler) renderAPITokens(w http.ResponseWriter, r *http.Request, problem, newToken string) {
+	ctx := r.Context()
+
+	tokens, err := h.storage.GetAPITokens(ctx, auth.UserFromContext(ctx).Id)
+	if thatWasAnError(ctx, w, "error getting api tokens", err) {
+		return
+	}
+
+	views.APITokens(tokens, problem, newToken).Render(ctx, w)
+}
+
+// requireUser returns the logged in user, sending anyone else off to log in first.
+func requireUser(w http.ResponseWriter, r *http.Request) *store.User {
+	user := auth.UserFromContext(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/login?"+url.Values{"next": {r.URL.RequestURI()}}.Encode(), http.StatusSeeOther)
+	}
+	return user
+}
+
+func getFormAPIToken(r *http.Request) (*store.APIToken, error) {
+	name := strings.TrimSpace(r.FormValue(`name`))
+	if len(name) == 0 || len(name) > constants.MaxAPITokenNameLength {
+		return nil, store.NewBadRequestError(fmt.Sprintf(`name must be between 1 and %d characters`, constants.MaxAPITokenNameLength))
+	}
+
+	var boardNames []string
+	for _, boardName := range strings.FieldsFunc(r.FormValue(`boards`), func(c rune) bool { return c == ',' || c == ' ' }) {
+		if !slices.Contains(boardNames, boardName) {
+			boardNames = append(boardNames, boardName)
+		}
+	}
+	if len(boardNames) == 0 || len(boardNames) > constants.MaxAPITokenBoards {
+		return nil, store.NewBadRequestError(fmt.Sprintf(`tokens can be used on between 1 and %d boards`, constants.MaxAPITokenBoards))
+	}
+
+	days, err := strconv.Atoi(r.FormValue(`days`))
+	if err != nil || !slices.Contains(constants.APITokenLifetimes, days) {
+		return nil, store.NewBadRequestError(`pick how long the token should last`)
+	}
+
+	now := time.Now()
+	return &store.APIToken{
+		Name:       name,
+		BoardNames: boardNames,
+		Write:      r.FormValue(`access`) == "write",
+		CreatedAt:  now,
+		ExpiresAt:  now.AddDate(0, 0, days),
+	}, nil
+}
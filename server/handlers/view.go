@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/danharasymiw/danban/server/constants"
+	"github.com/danharasymiw/danban/server/store"
+	"github.com/danharasymiw/danban/server/ui/components"
+)
+
+// AddView saves the board's current view options under a name, then goes to the view's own URL so it can be shared.
+func (h *Handler) AddView(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+
+	name := r.FormValue(`viewName`)
+	if len(name) == 0 || len(name) > constants.MaxViewNameLength {
+		thatWasAnError(ctx, w, "invalid view name", store.NewBadRequestError(fmt.Sprintf(`view name must be between 1 and %d characters`, constants.MaxViewNameLength)))
+		return
+	}
+
+	view := &store.View{
+		Name:     name,
+		Filter:   r.FormValue(`filter`),
+		Assignee: r.FormValue(`assignee`),
+		Sort:     r.FormValue(`sort`),
+		Lanes:    r.FormValue(`lanes`),
+		// FormValue has already parsed the form.
+		CollapsedColumnIds: r.Form["collapsed"],
+	}
+	err := h.storage.AddView(ctx, boardName, view)
+	if thatWasAnError(ctx, w, "error adding view", err) {
+		return
+	}
+
+	w.Header().Set("HX-Redirect", components.ViewURL(boardName, view.Id))
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Handler) DeleteView(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardName := chi.URLParam(r, "boardName")
+	viewId := chi.URLParam(r, "viewId")
+
+	err := h.storage.DeleteView(ctx, boardName, viewId)
+	if thatWasAnError(ctx, w, "error deleting view", err) {
+		return
+	}
+
+	w.Header().Set("HX-Redirect", fmt.Sprintf("/board/%s", boardName))
+	w.WriteHeader(http.StatusOK)
+}
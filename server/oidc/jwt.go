@@ -0,0 +1,163 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type keySet struct {
+	Keys []jwk `json:"keys"`
+}
+
+// verifySignature checks the token was signed by one of the provider's keys, returning its payload.
+// Only RS256 and ES256 are supported, which covers what providers sign ID tokens with by default.
+func (p *Provider) verifySignature(ctx context.Context, rawToken string) ([]byte, error) {
+	parts := strings.Split(rawToken, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("id token isn't a jwt")
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode id token header: %w", err)
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	err = json.Unmarshal(headerJSON, &header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode id token header: %w", err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode id token signature: %w", err)
+	}
+
+	key, err := p.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	switch header.Alg {
+	case "RS256":
+		pub, err := key.rsaPublicKey()
+		if err != nil {
+			return nil, err
+		}
+		err = rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature)
+		if err != nil {
+			return nil, fmt.Errorf("id token signature is invalid: %w", err)
+		}
+	case "ES256":
+		pub, err := key.ecdsaPublicKey()
+		if err != nil {
+			return nil, err
+		}
+		if len(signature) != 64 {
+			return nil, fmt.Errorf("id token signature is invalid")
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(pub, digest[:], r, s) {
+			return nil, fmt.Errorf("id token signature is invalid")
+		}
+	default:
+		return nil, fmt.Errorf("id token is signed with unsupported algorithm %q", header.Alg)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode id token payload: %w", err)
+	}
+	return payload, nil
+}
+
+// key finds the provider's signing key, fetching its keys again in case it's been rotated since they were cached.
+func (p *Provider) key(ctx context.Context, kid string) (*jwk, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for refreshed := false; ; refreshed = true {
+		if p.keys != nil {
+			for i, key := range p.keys.Keys {
+				if key.Kid == kid || (kid == `` && len(p.keys.Keys) == 1) {
+					return &p.keys.Keys[i], nil
+				}
+			}
+		}
+		if refreshed {
+			return nil, fmt.Errorf("oidc provider has no key %q", kid)
+		}
+
+		var keys keySet
+		err = p.getJSON(ctx, d.JWKSURI, &keys)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get oidc provider keys: %w", err)
+		}
+		p.keys = &keys
+	}
+}
+
+func (k *jwk) rsaPublicKey() (*rsa.PublicKey, error) {
+	if k.Kty != "RSA" {
+		return nil, fmt.Errorf("key %q isn't an RSA key", k.Kid)
+	}
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode key %q: %w", k.Kid, err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode key %q: %w", k.Kid, err)
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
+
+func (k *jwk) ecdsaPublicKey() (*ecdsa.PublicKey, error) {
+	if k.Kty != "EC" || k.Crv != "P-256" {
+		return nil, fmt.Errorf("key %q isn't a P-256 key", k.Kid)
+	}
+	x, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode key %q: %w", k.Kid, err)
+	}
+	y, err := base64.RawURLEncoding.DecodeString(k.Y)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode key %q: %w", k.Kid, err)
+	}
+	return &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}, nil
+}
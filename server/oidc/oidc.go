@@ -0,0 +1,278 @@
+// Package oidc logs people in through an OpenID Connect provider, using the authorization code flow with PKCE.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config is read from OIDC_ISSUER, OIDC_CLIENT_ID, OIDC_CLIENT_SECRET and optionally OIDC_REDIRECT_URL.
+type Config struct {
+	Issuer       string
+	ClientId     string
+	ClientSecret string
+	// RedirectURL is where the provider sends people back to, it defaults to /login/oidc/callback on the same host.
+	RedirectURL string
+}
+
+// ConfigFromEnv returns the provider's config, or nil when OIDC_ISSUER isn't set.
+func ConfigFromEnv() *Config {
+	issuer := os.Getenv("OIDC_ISSUER")
+	if issuer == `` {
+		return nil
+	}
+	return &Config{
+		Issuer:       strings.TrimSuffix(issuer, "/"),
+		ClientId:     os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+	}
+}
+
+// Claims are what the provider vouches for about whoever logged in.
+type Claims struct {
+	Issuer            string   `json:"iss"`
+	Subject           string   `json:"sub"`
+	Email             string   `json:"email"`
+	EmailVerified     bool     `json:"email_verified"`
+	Name              string   `json:"name"`
+	PreferredUsername string   `json:"preferred_username"`
+	Nonce             string   `json:"nonce"`
+	Audience          audience `json:"aud"`
+	Expiry            int64    `json:"exp"`
+}
+
+// audience can be a single string or a list of them.
+type audience []string
+
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var single string
+	if err := json.Unmarshal(b, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(b, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider talks to the OIDC provider. Its endpoints are discovered the first time they're needed, so the server can
+// start before the provider is reachable.
+type Provider struct {
+	config *Config
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *discovery
+	keys      *keySet
+}
+
+func NewProvider(config *Config) *Provider {
+	return &Provider{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// RedirectURL is where the provider should send people back to after a request to r.
+func (p *Provider) RedirectURL(r *http.Request) string {
+	if p.config.RedirectURL != `` {
+		return p.config.RedirectURL
+	}
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s/login/oidc/callback", scheme, r.Host)
+}
+
+// AuthCodeURL is where to send someone to log in. The verifier is kept for Exchange, and only its hash is sent.
+func (p *Provider) AuthCodeURL(ctx context.Context, redirectURL, state, nonce, verifier string) (string, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return ``, err
+	}
+
+	challenge := sha256.Sum256([]byte(verifier))
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientId},
+		"redirect_uri":          {redirectURL},
+		"scope":                 {"openid email profile"},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	sep := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return d.AuthorizationEndpoint + sep + query.Encode(), nil
+}
+
+// Exchange trades the code the provider sent back for an ID token, returning its claims once they've been verified.
+func (p *Provider) Exchange(ctx context.Context, redirectURL, code, verifier, nonce string) (*Claims, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURL},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.config.ClientId), url.QueryEscape(p.config.ClientSecret))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token request failed with %s: %s", resp.Status, body)
+	}
+
+	var token struct {
+		IdToken string `json:"id_token"`
+	}
+	err = json.Unmarshal(body, &token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if token.IdToken == `` {
+		return nil, fmt.Errorf("token response had no id token")
+	}
+
+	claims, err := p.verify(ctx, token.IdToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Nonce != nonce {
+		return nil, fmt.Errorf("id token nonce doesn't match")
+	}
+	return claims, nil
+}
+
+func (p *Provider) verify(ctx context.Context, rawToken string) (*Claims, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := p.verifySignature(ctx, rawToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	err = json.Unmarshal(payload, &claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode id token claims: %w", err)
+	}
+
+	if claims.Issuer != d.Issuer {
+		return nil, fmt.Errorf("id token is from %s rather than %s", claims.Issuer, d.Issuer)
+	}
+	if !contains(claims.Audience, p.config.ClientId) {
+		return nil, fmt.Errorf("id token isn't meant for this client")
+	}
+	if time.Now().After(time.Unix(claims.Expiry, 0)) {
+		return nil, fmt.Errorf("id token has expired")
+	}
+	if claims.Subject == `` {
+		return nil, fmt.Errorf("id token has no subject")
+	}
+	return &claims, nil
+}
+
+func (p *Provider) getDiscovery(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var d discovery
+	err := p.getJSON(ctx, p.config.Issuer+"/.well-known/openid-configuration", &d)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover oidc provider: %w", err)
+	}
+	if d.Issuer != p.config.Issuer {
+		return nil, fmt.Errorf("oidc provider says its issuer is %s rather than %s", d.Issuer, p.config.Issuer)
+	}
+
+	p.discovery = &d
+	return p.discovery, nil
+}
+
+func (p *Provider) getJSON(ctx context.Context, u string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s failed with %s", u, resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
+
+// RandomString makes the unguessable state, nonce and PKCE verifier values for a login.
+func RandomString() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
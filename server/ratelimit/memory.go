@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// pruneInterval is how often buckets that have filled back up are forgotten.
+const pruneInterval = time.Minute
+
+// MemoryStore keeps buckets in memory, so each server limits visitors on its own.
+type MemoryStore struct {
+	mu         sync.Mutex
+	buckets    map[string]*bucket
+	lastPruned time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	limit   Limit
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: map[string]*bucket{},
+	}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastPruned) > pruneInterval {
+		s.prune(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now, limit: limit}
+		s.buckets[key] = b
+	}
+	b.refill(now)
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) * float64(limit.Every)), nil
+	}
+	b.tokens--
+	return true, 0, nil
+}
+
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updated)
+	if elapsed <= 0 {
+		return
+	}
+	b.tokens = math.Min(float64(b.limit.Burst), b.tokens+float64(elapsed)/float64(b.limit.Every))
+	b.updated = now
+}
+
+// prune forgets full buckets, which act the same as ones that were never made.
+func (s *MemoryStore) prune(now time.Time) {
+	for key, b := range s.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.limit.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastPruned = now
+}
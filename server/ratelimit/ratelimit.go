@@ -0,0 +1,95 @@
+// Package ratelimit limits how often each visitor can do something, using token buckets.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/danharasymiw/danban/server/logger"
+)
+
+// Limit is a token bucket that holds up to Burst tokens, with another added every Every.
+type Limit struct {
+	Burst int
+	Every time.Duration
+}
+
+// Store keeps everyone's buckets. The in memory store is enough for a single server, several servers would need
+// to share one.
+type Store interface {
+	// Take takes a token from the key's bucket, reporting whether there was one. When there wasn't it also
+	// reports how long until there will be.
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (bool, time.Duration, error)
+}
+
+// Limiter limits how often each IP address can do one thing.
+type Limiter struct {
+	store Store
+	name  string
+	limit Limit
+}
+
+// New makes a limiter for the named thing, keeping its buckets in the store. Limiters can share a store as long as
+// their names are different.
+func New(store Store, name string, limit Limit) *Limiter {
+	return &Limiter{
+		store: store,
+		name:  name,
+		limit: limit,
+	}
+}
+
+// Allow takes a token for the request's IP address. When there aren't any left it responds with a 429 and returns
+// false, so the caller shouldn't go any further. A nil limiter allows everything.
+func (l *Limiter) Allow(w http.ResponseWriter, r *http.Request) bool {
+	if l == nil {
+		return true
+	}
+	ctx := r.Context()
+
+	ok, wait, err := l.store.Take(ctx, l.name+":"+clientIP(r), l.limit, time.Now())
+	if err != nil {
+		// Being unable to rate limit shouldn't take the site down with it.
+		logger.New(ctx).WithError(err).Error("Failed to check rate limit")
+		return true
+	}
+	if ok {
+		return true
+	}
+
+	seconds := int(math.Ceil(wait.Seconds()))
+	logger.New(ctx).Warnf("Rate limited %s for %s", clientIP(r), l.name)
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	http.Error(w, fmt.Sprintf("slow down, try again in %d seconds", seconds), http.StatusTooManyRequests)
+	return false
+}
+
+// Mutations limits requests that change something, leaving the ones that only look alone.
+func (l *Limiter) Mutations(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			if !l.Allow(w, r) {
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	}
+	return http.HandlerFunc(fn)
+}
+
+// clientIP is the address the request came from. Behind a proxy that's only the visitor's address when the proxy's
+// headers are trusted, see middleware.RealIP.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
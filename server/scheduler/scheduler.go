@@ -0,0 +1,77 @@
+// Package scheduler runs the board's background jobs in-process. Every replica runs a scheduler, but only the one
+// holding the leader lock in storage does any work, so jobs aren't run twice.
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"time"
+
+	"github.com/danharasymiw/danban/server/logger"
+	"github.com/danharasymiw/danban/server/store"
+)
+
+const (
+	leaderLock = "scheduler-leader"
+	// Interval is how often the jobs run.
+	Interval = 15 * time.Minute
+	// The leader keeps renewing its lock each run, and only loses it if it misses a couple of them.
+	leaderLockTTL = 2*Interval + time.Minute
+)
+
+type Scheduler struct {
+	storage store.Storage
+	// holder identifies this replica when taking the leader lock.
+	holder string
+}
+
+func New(storage store.Storage) *Scheduler {
+	hostname, _ := os.Hostname()
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+
+	return &Scheduler{
+		storage: storage,
+		holder:  hostname + "-" + hex.EncodeToString(suffix),
+	}
+}
+
+// Run runs the jobs every Interval until the context is done.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(Interval)
+	defer ticker.Stop()
+
+	for {
+		s.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) runOnce(ctx context.Context) {
+	log := logger.New(ctx).WithField("holder", s.holder)
+
+	leader, err := s.storage.TryLock(ctx, leaderLock, s.holder, leaderLockTTL)
+	if err != nil {
+		log.WithError(err).Error("Failed to take the scheduler leader lock")
+		return
+	}
+	if !leader {
+		return
+	}
+
+	archived, err := s.storage.AutoArchiveCards(ctx, time.Now())
+	if err != nil {
+		log.WithError(err).Error("Failed to auto archive cards")
+		return
+	}
+	if archived > 0 {
+		log.Infof("Auto archived %d cards", archived)
+	}
+}
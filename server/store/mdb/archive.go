@@ -0,0 +1,233 @@
+package mdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/danharasymiw/danban/server/store"
+)
+
+// archivedIndex is the index archived cards are given, they're out of their column's order until they're restored.
+const archivedIndex = -1
+
+func (m *MongoDb) ArchiveCard(ctx context.Context, cardIdStr string) error {
+	cardId, err := primitive.ObjectIDFromHex(cardIdStr)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid card id: %s", cardIdStr))
+	}
+
+	var card card
+	err = m.cardCol.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": cardId, "archivedAt": nil},
+		bson.M{"$set": bson.M{"archivedAt": time.Now(), "index": archivedIndex}},
+	).Decode(&card)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return store.NewNotFoundError("unarchived card", cardIdStr)
+		}
+		return fmt.Errorf("failed to archive card: %w", err)
+	}
+
+	_, err = m.cardCol.UpdateMany(
+		ctx,
+		bson.M{
+			"columnId": card.ColumnId,
+			"index":    bson.M{"$gt": card.Index},
+		},
+		bson.M{"$inc": bson.M{"index": -1}},
+	)
+	if err != nil {
+		return fmt.Errorf("error shifting card indices during archive: %w", err)
+	}
+	return nil
+}
+
+func (m *MongoDb) ArchiveColumnCards(ctx context.Context, columnIdStr string) (int, error) {
+	columnId, err := primitive.ObjectIDFromHex(columnIdStr)
+	if err != nil {
+		return 0, store.NewBadRequestError(fmt.Sprintf("invalid column id: %s", columnIdStr))
+	}
+
+	// Every card is leaving, so there's nothing left to shift up.
+	result, err := m.cardCol.UpdateMany(
+		ctx,
+		bson.M{"columnId": columnId, "archivedAt": nil},
+		bson.M{"$set": bson.M{"archivedAt": time.Now(), "index": archivedIndex}},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive column cards: %w", err)
+	}
+	return int(result.ModifiedCount), nil
+}
+
+// RestoreCard puts an archived card back at the bottom of the column it was archived from.
+func (m *MongoDb) RestoreCard(ctx context.Context, cardIdStr string) error {
+	cardId, err := primitive.ObjectIDFromHex(cardIdStr)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid card id: %s", cardIdStr))
+	}
+
+	var card card
+	err = m.cardCol.FindOne(ctx, bson.M{"_id": cardId, "archivedAt": bson.M{"$ne": nil}}).Decode(&card)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return store.NewNotFoundError("archived card", cardIdStr)
+		}
+		return fmt.Errorf("unexpected error getting archived card: %w", err)
+	}
+
+	count, err := m.GetCardCount(ctx, card.ColumnId.Hex())
+	if err != nil {
+		return err
+	}
+
+	_, err = m.cardCol.UpdateOne(
+		ctx,
+		bson.M{"_id": cardId},
+		bson.M{
+			"$set":   bson.M{"index": count, "movedAt": time.Now()},
+			"$unset": bson.M{"archivedAt": ""},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to restore card: %w", err)
+	}
+	return nil
+}
+
+// GetArchivedCards returns the board's archived cards, most recently archived first.
+func (m *MongoDb) GetArchivedCards(ctx context.Context, boardName string) ([]*store.Card, error) {
+	var board board
+	err := m.boardCol.FindOne(ctx, bson.M{"name": boardName}).Decode(&board)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, store.NewNotFoundError("board", boardName)
+		}
+		return nil, fmt.Errorf("unexpected error getting board: %w", err)
+	}
+
+	cursor, err := m.cardCol.Find(
+		ctx,
+		bson.M{
+			"columnId":   bson.M{"$in": board.ColumnIds},
+			"archivedAt": bson.M{"$ne": nil},
+		},
+		options.Find().SetSort(bson.M{"archivedAt": -1}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived cards: %w", err)
+	}
+
+	var cards []card
+	err = cursor.All(ctx, &cards)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode archived cards: %w", err)
+	}
+
+	labels := toStoreLabels(board.Labels)
+	members := toStoreMembers(board.Members)
+
+	storeCards := make([]*store.Card, 0, len(cards))
+	for _, c := range cards {
+		storeCards = append(storeCards, toStoreCard(&c, labels, members))
+	}
+	return storeCards, nil
+}
+
+// AutoArchiveCards archives the cards that have been in a done column for longer than their board's AutoArchiveDays.
+// Cards from before their move time was kept go by when they were created instead.
+func (m *MongoDb) AutoArchiveCards(ctx context.Context, now time.Time) (int, error) {
+	cursor, err := m.boardCol.Find(ctx, bson.M{"autoArchiveDays": bson.M{"$gt": 0}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to find boards that auto archive: %w", err)
+	}
+	var boards []board
+	err = cursor.All(ctx, &boards)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode boards that auto archive: %w", err)
+	}
+
+	archived := 0
+	for _, board := range boards {
+		cursor, err := m.columnCol.Find(ctx, bson.M{"_id": bson.M{"$in": board.ColumnIds}, "done": true})
+		if err != nil {
+			return archived, fmt.Errorf("failed to find done columns: %w", err)
+		}
+		var columns []column
+		err = cursor.All(ctx, &columns)
+		if err != nil {
+			return archived, fmt.Errorf("failed to decode done columns: %w", err)
+		}
+
+		cutoff := now.Add(-time.Duration(board.AutoArchiveDays) * 24 * time.Hour)
+		for _, column := range columns {
+			result, err := m.cardCol.UpdateMany(
+				ctx,
+				bson.M{
+					"columnId":   column.Id,
+					"archivedAt": nil,
+					"$or": []bson.M{
+						{"movedAt": bson.M{"$lt": cutoff}},
+						{"movedAt": bson.M{"$exists": false}, "_id": bson.M{"$lt": primitive.NewObjectIDFromTimestamp(cutoff)}},
+					},
+				},
+				bson.M{"$set": bson.M{"archivedAt": now, "index": archivedIndex}},
+			)
+			if err != nil {
+				return archived, fmt.Errorf("failed to auto archive cards: %w", err)
+			}
+			if result.ModifiedCount == 0 {
+				continue
+			}
+			archived += int(result.ModifiedCount)
+
+			err = m.reindexColumn(ctx, column.Id)
+			if err != nil {
+				return archived, err
+			}
+		}
+	}
+	return archived, nil
+}
+
+// reindexColumn closes the gaps left in a column's card order after cards were taken out from all over it.
+func (m *MongoDb) reindexColumn(ctx context.Context, columnId primitive.ObjectID) error {
+	cursor, err := m.cardCol.Find(
+		ctx,
+		bson.M{"columnId": columnId, "archivedAt": nil},
+		options.Find().SetSort(bson.M{"index": 1}).SetProjection(bson.M{"index": 1}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get column cards: %w", err)
+	}
+	var cards []card
+	err = cursor.All(ctx, &cards)
+	if err != nil {
+		return fmt.Errorf("failed to decode column cards: %w", err)
+	}
+
+	var updates []mongo.WriteModel
+	for i, c := range cards {
+		if c.Index != i {
+			updates = append(updates, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": c.Id}).
+				SetUpdate(bson.M{"$set": bson.M{"index": i}}))
+		}
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	_, err = m.cardCol.BulkWrite(ctx, updates)
+	if err != nil {
+		return fmt.Errorf("failed to reindex column cards: %w", err)
+	}
+	return nil
+}
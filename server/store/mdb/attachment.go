@@ -0,0 +1,80 @@
+package mdb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/danharasymiw/danban/server/store"
+)
+
+func (m *MongoDb) AddAttachment(ctx context.Context, cardIdStr string, attachmentDTO *store.Attachment) error {
+	cardId, err := primitive.ObjectIDFromHex(cardIdStr)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid card id: %s", cardIdStr))
+	}
+	newAttachment := attachment{
+		Id:           primitive.NewObjectID(),
+		Name:         attachmentDTO.Name,
+		ContentType:  attachmentDTO.ContentType,
+		Size:         attachmentDTO.Size,
+		HasThumbnail: attachmentDTO.HasThumbnail,
+		CreatedAt:    attachmentDTO.CreatedAt,
+	}
+
+	result, err := m.cardCol.UpdateOne(
+		ctx,
+		bson.M{"_id": cardId},
+		bson.M{"$push": bson.M{"attachments": newAttachment}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add attachment: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return store.NewNotFoundError("card", cardIdStr)
+	}
+
+	attachmentDTO.Id = newAttachment.Id.Hex()
+	return nil
+}
+
+func (m *MongoDb) DeleteAttachment(ctx context.Context, cardIdStr, attachmentIdStr string) error {
+	cardId, err := primitive.ObjectIDFromHex(cardIdStr)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid card id: %s", cardIdStr))
+	}
+	attachmentId, err := primitive.ObjectIDFromHex(attachmentIdStr)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid attachment id: %s", attachmentIdStr))
+	}
+
+	result, err := m.cardCol.UpdateOne(
+		ctx,
+		bson.M{"_id": cardId},
+		bson.M{"$pull": bson.M{"attachments": bson.M{"_id": attachmentId}}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return store.NewNotFoundError("card", cardIdStr)
+	}
+	return nil
+}
+
+func toStoreAttachments(attachments []attachment) []*store.Attachment {
+	storeAttachments := make([]*store.Attachment, 0, len(attachments))
+	for _, a := range attachments {
+		storeAttachments = append(storeAttachments, &store.Attachment{
+			Id:           a.Id.Hex(),
+			Name:         a.Name,
+			ContentType:  a.ContentType,
+			Size:         a.Size,
+			HasThumbnail: a.HasThumbnail,
+			CreatedAt:    a.CreatedAt,
+		})
+	}
+	return storeAttachments
+}
@@ -0,0 +1,164 @@
+package mdb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/danharasymiw/danban/server/store"
+)
+
+func (m *MongoDb) AddCardLink(ctx context.Context, cardIdStr, otherCardIdStr string, typ store.CardLinkType) error {
+	cardId, err := primitive.ObjectIDFromHex(cardIdStr)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid card id: %s", cardIdStr))
+	}
+	otherCardId, err := primitive.ObjectIDFromHex(otherCardIdStr)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid card id: %s", otherCardIdStr))
+	}
+	if cardId == otherCardId {
+		return store.NewBadRequestError("a card cannot be linked to itself")
+	}
+
+	count, err := m.cardCol.CountDocuments(ctx, bson.M{"_id": otherCardId})
+	if err != nil {
+		return fmt.Errorf("failed to look up linked card: %w", err)
+	}
+	if count == 0 {
+		return store.NewNotFoundError("card", otherCardIdStr)
+	}
+
+	newLink := &cardLink{
+		FromCardId: cardId,
+		ToCardId:   otherCardId,
+		Type:       string(typ),
+	}
+	if typ == store.CardLinkBlockedBy || typ == store.CardLinkDuplicatedBy {
+		newLink.FromCardId, newLink.ToCardId = otherCardId, cardId
+		newLink.Type = string(typ.Inverse())
+	}
+
+	_, err = m.linkCol.InsertOne(ctx, newLink)
+	if err != nil {
+		return fmt.Errorf("failed to insert card link: %w", err)
+	}
+	return nil
+}
+
+func (m *MongoDb) DeleteCardLink(ctx context.Context, linkIdStr string) error {
+	linkId, err := primitive.ObjectIDFromHex(linkIdStr)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid link id: %s", linkIdStr))
+	}
+
+	result, err := m.linkCol.DeleteOne(ctx, bson.M{"_id": linkId})
+	if err != nil {
+		return fmt.Errorf("failed to delete card link: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return store.NewNotFoundError("card link", linkIdStr)
+	}
+	return nil
+}
+
+func (m *MongoDb) GetCardLinks(ctx context.Context, cardIdStr string) ([]*store.CardLink, error) {
+	cardId, err := primitive.ObjectIDFromHex(cardIdStr)
+	if err != nil {
+		return nil, store.NewBadRequestError(fmt.Sprintf("invalid card id: %s", cardIdStr))
+	}
+
+	cursor, err := m.linkCol.Find(ctx, bson.M{"$or": []bson.M{
+		{"fromCardId": cardId},
+		{"toCardId": cardId},
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find card links: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var links []cardLink
+	if err := cursor.All(ctx, &links); err != nil {
+		return nil, fmt.Errorf("failed to decode card links: %w", err)
+	}
+
+	storeLinks := make([]*store.CardLink, 0, len(links))
+	for _, link := range links {
+		typ := store.CardLinkType(link.Type)
+		otherCardId := link.ToCardId
+		if link.ToCardId == cardId {
+			typ = typ.Inverse()
+			otherCardId = link.FromCardId
+		}
+
+		// Linked cards can live on other boards, so look each up along with its own board.
+		otherCard, err := m.GetCard(ctx, otherCardId.Hex())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get linked card: %w", err)
+		}
+		columnId, _ := primitive.ObjectIDFromHex(otherCard.ColumnId)
+		board, err := m.getBoardByColumnId(ctx, columnId)
+		if err != nil {
+			return nil, err
+		}
+
+		storeLinks = append(storeLinks, &store.CardLink{
+			Id:        link.Id.Hex(),
+			Type:      typ,
+			Card:      otherCard,
+			BoardName: board.Name,
+		})
+	}
+	return storeLinks, nil
+}
+
+// blockedCardIds finds which of the cards are blocked by a card that isn't in a done column.
+func (m *MongoDb) blockedCardIds(ctx context.Context, cardIds []primitive.ObjectID) (map[primitive.ObjectID]bool, error) {
+	blocked := map[primitive.ObjectID]bool{}
+	if len(cardIds) == 0 {
+		return blocked, nil
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"type":     string(store.CardLinkBlocks),
+			"toCardId": bson.M{"$in": cardIds},
+		}},
+		{"$lookup": bson.M{
+			"from":         "cards",
+			"localField":   "fromCardId",
+			"foreignField": "_id",
+			"as":           "blocker",
+		}},
+		{"$unwind": "$blocker"},
+		// Archived cards are out of play, so they don't hold anything up.
+		{"$match": bson.M{"blocker.archivedAt": nil}},
+		{"$lookup": bson.M{
+			"from":         "columns",
+			"localField":   "blocker.columnId",
+			"foreignField": "_id",
+			"as":           "blockerColumn",
+		}},
+		{"$unwind": "$blockerColumn"},
+		{"$match": bson.M{"blockerColumn.done": bson.M{"$ne": true}}},
+		{"$project": bson.M{"toCardId": 1}},
+	}
+
+	cursor, err := m.linkCol.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate blocking cards: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var links []cardLink
+	if err := cursor.All(ctx, &links); err != nil {
+		return nil, fmt.Errorf("failed to decode blocking cards: %w", err)
+	}
+
+	for _, link := range links {
+		blocked[link.ToCardId] = true
+	}
+	return blocked, nil
+}
@@ -0,0 +1,173 @@
+package mdb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/danharasymiw/danban/server/store"
+)
+
+func (m *MongoDb) AddChecklist(ctx context.Context, cardIdStr string, checklistDTO *store.Checklist) error {
+	cardId, err := primitive.ObjectIDFromHex(cardIdStr)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid card id: %s", cardIdStr))
+	}
+
+	newChecklist := checklist{
+		Id:    primitive.NewObjectID(),
+		Name:  checklistDTO.Name,
+		Items: []checklistItem{},
+	}
+
+	result, err := m.cardCol.UpdateOne(
+		ctx,
+		bson.M{"_id": cardId},
+		bson.M{"$push": bson.M{"checklists": newChecklist}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add checklist: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return store.NewNotFoundError("card", cardIdStr)
+	}
+
+	checklistDTO.Id = newChecklist.Id.Hex()
+	return nil
+}
+
+func (m *MongoDb) DeleteChecklist(ctx context.Context, cardIdStr, checklistIdStr string) error {
+	cardId, checklistId, err := checklistIds(cardIdStr, checklistIdStr)
+	if err != nil {
+		return err
+	}
+
+	result, err := m.cardCol.UpdateOne(
+		ctx,
+		bson.M{"_id": cardId},
+		bson.M{"$pull": bson.M{"checklists": bson.M{"_id": checklistId}}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete checklist: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return store.NewNotFoundError("card", cardIdStr)
+	}
+	return nil
+}
+
+func (m *MongoDb) AddChecklistItem(ctx context.Context, cardIdStr, checklistIdStr string, itemDTO *store.ChecklistItem) error {
+	cardId, checklistId, err := checklistIds(cardIdStr, checklistIdStr)
+	if err != nil {
+		return err
+	}
+
+	newItem := checklistItem{
+		Id:   primitive.NewObjectID(),
+		Text: itemDTO.Text,
+	}
+
+	result, err := m.cardCol.UpdateOne(
+		ctx,
+		bson.M{"_id": cardId, "checklists._id": checklistId},
+		bson.M{"$push": bson.M{"checklists.$.items": newItem}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add checklist item: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return store.NewNotFoundError("checklist", checklistIdStr)
+	}
+
+	itemDTO.Id = newItem.Id.Hex()
+	return nil
+}
+
+func (m *MongoDb) SetChecklistItemDone(ctx context.Context, cardIdStr, checklistIdStr, itemIdStr string, done bool) error {
+	cardId, checklistId, err := checklistIds(cardIdStr, checklistIdStr)
+	if err != nil {
+		return err
+	}
+	itemId, err := primitive.ObjectIDFromHex(itemIdStr)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid checklist item id: %s", itemIdStr))
+	}
+
+	result, err := m.cardCol.UpdateOne(
+		ctx,
+		bson.M{"_id": cardId},
+		bson.M{"$set": bson.M{"checklists.$[checklist].items.$[item].done": done}},
+		options.Update().SetArrayFilters(options.ArrayFilters{
+			Filters: []interface{}{
+				bson.M{"checklist._id": checklistId},
+				bson.M{"item._id": itemId},
+			},
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update checklist item: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return store.NewNotFoundError("card", cardIdStr)
+	}
+	return nil
+}
+
+func (m *MongoDb) DeleteChecklistItem(ctx context.Context, cardIdStr, checklistIdStr, itemIdStr string) error {
+	cardId, checklistId, err := checklistIds(cardIdStr, checklistIdStr)
+	if err != nil {
+		return err
+	}
+	itemId, err := primitive.ObjectIDFromHex(itemIdStr)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid checklist item id: %s", itemIdStr))
+	}
+
+	result, err := m.cardCol.UpdateOne(
+		ctx,
+		bson.M{"_id": cardId, "checklists._id": checklistId},
+		bson.M{"$pull": bson.M{"checklists.$.items": bson.M{"_id": itemId}}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete checklist item: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return store.NewNotFoundError("checklist", checklistIdStr)
+	}
+	return nil
+}
+
+func checklistIds(cardIdStr, checklistIdStr string) (primitive.ObjectID, primitive.ObjectID, error) {
+	cardId, err := primitive.ObjectIDFromHex(cardIdStr)
+	if err != nil {
+		return cardId, primitive.NilObjectID, store.NewBadRequestError(fmt.Sprintf("invalid card id: %s", cardIdStr))
+	}
+	checklistId, err := primitive.ObjectIDFromHex(checklistIdStr)
+	if err != nil {
+		return cardId, checklistId, store.NewBadRequestError(fmt.Sprintf("invalid checklist id: %s", checklistIdStr))
+	}
+	return cardId, checklistId, nil
+}
+
+func toStoreChecklists(checklists []checklist) []*store.Checklist {
+	storeChecklists := make([]*store.Checklist, 0, len(checklists))
+	for _, cl := range checklists {
+		items := make([]*store.ChecklistItem, 0, len(cl.Items))
+		for _, item := range cl.Items {
+			items = append(items, &store.ChecklistItem{
+				Id:   item.Id.Hex(),
+				Text: item.Text,
+				Done: item.Done,
+			})
+		}
+		storeChecklists = append(storeChecklists, &store.Checklist{
+			Id:    cl.Id.Hex(),
+			Name:  cl.Name,
+			Items: items,
+		})
+	}
+	return storeChecklists
+}
@@ -0,0 +1,142 @@
+package mdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/danharasymiw/danban/server/store"
+)
+
+func (m *MongoDb) AddComment(ctx context.Context, commentDTO *store.Comment) error {
+	cardId, err := primitive.ObjectIDFromHex(commentDTO.CardId)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid card id: %s", commentDTO.CardId))
+	}
+	authorId, err := primitive.ObjectIDFromHex(commentDTO.AuthorId)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid author id: %s", commentDTO.AuthorId))
+	}
+
+	newComment := &comment{
+		CardId:     cardId,
+		AuthorId:   authorId,
+		AuthorName: commentDTO.AuthorName,
+		Body:       commentDTO.Body,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	result, err := m.commentCol.InsertOne(ctx, newComment)
+	if err != nil {
+		return fmt.Errorf("failed to insert comment: %w", err)
+	}
+
+	commentDTO.Id = result.InsertedID.(primitive.ObjectID).Hex()
+	commentDTO.CreatedAt = newComment.CreatedAt
+	return nil
+}
+
+func (m *MongoDb) EditComment(ctx context.Context, commentDTO *store.Comment) error {
+	commentId, err := primitive.ObjectIDFromHex(commentDTO.Id)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid comment id: %s", commentDTO.Id))
+	}
+
+	editedAt := time.Now().UTC()
+	result, err := m.commentCol.UpdateOne(
+		ctx,
+		bson.M{"_id": commentId},
+		bson.M{"$set": bson.M{
+			"body":     commentDTO.Body,
+			"editedAt": editedAt,
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return store.NewNotFoundError("comment", commentDTO.Id)
+	}
+
+	commentDTO.EditedAt = &editedAt
+	return nil
+}
+
+func (m *MongoDb) DeleteComment(ctx context.Context, commentIdStr string) error {
+	commentId, err := primitive.ObjectIDFromHex(commentIdStr)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid comment id: %s", commentIdStr))
+	}
+
+	result, err := m.commentCol.DeleteOne(ctx, bson.M{"_id": commentId})
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return store.NewNotFoundError("comment", commentIdStr)
+	}
+	return nil
+}
+
+func (m *MongoDb) GetComment(ctx context.Context, commentIdStr string) (*store.Comment, error) {
+	commentId, err := primitive.ObjectIDFromHex(commentIdStr)
+	if err != nil {
+		return nil, store.NewBadRequestError(fmt.Sprintf("invalid comment id: %s", commentIdStr))
+	}
+
+	var comment comment
+	err = m.commentCol.FindOne(ctx, bson.M{"_id": commentId}).Decode(&comment)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, store.NewNotFoundError("comment", commentIdStr)
+		}
+		return nil, fmt.Errorf("unexpected error getting comment: %w", err)
+	}
+
+	return toStoreComment(&comment), nil
+}
+
+func (m *MongoDb) GetComments(ctx context.Context, cardIdStr string) ([]*store.Comment, error) {
+	cardId, err := primitive.ObjectIDFromHex(cardIdStr)
+	if err != nil {
+		return nil, store.NewBadRequestError(fmt.Sprintf("invalid card id: %s", cardIdStr))
+	}
+
+	cursor, err := m.commentCol.Find(
+		ctx,
+		bson.M{"cardId": cardId},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find comments: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var comments []comment
+	if err := cursor.All(ctx, &comments); err != nil {
+		return nil, fmt.Errorf("failed to decode comments: %w", err)
+	}
+
+	storeComments := make([]*store.Comment, 0, len(comments))
+	for _, c := range comments {
+		storeComments = append(storeComments, toStoreComment(&c))
+	}
+	return storeComments, nil
+}
+
+func toStoreComment(c *comment) *store.Comment {
+	return &store.Comment{
+		Id:         c.Id.Hex(),
+		CardId:     c.CardId.Hex(),
+		AuthorId:   c.AuthorId.Hex(),
+		AuthorName: c.AuthorName,
+		Body:       c.Body,
+		CreatedAt:  c.CreatedAt,
+		EditedAt:   c.EditedAt,
+	}
+}
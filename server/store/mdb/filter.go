@@ -0,0 +1,169 @@
+package mdb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/danharasymiw/danban/server/store"
+)
+
+// cardFilterMatch translates a card filter into a $match stage for the cards of a board.
+// Label, member and column names are looked up on the board first, a name the board doesn't have matches no cards.
+func (m *MongoDb) cardFilterMatch(ctx context.Context, boardName string, filter *store.CardFilter) (bson.M, error) {
+	if filter == nil {
+		return bson.M{}, nil
+	}
+
+	var board board
+	err := m.boardCol.FindOne(ctx, bson.M{"name": boardName}).Decode(&board)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, store.NewNotFoundError("board", boardName)
+		}
+		return nil, fmt.Errorf("unexpected error getting board: %w", err)
+	}
+
+	var clauses []bson.M
+	addIn := func(field string, include, exclude []primitive.ObjectID, includeSet, excludeSet bool) {
+		if includeSet {
+			clauses = append(clauses, bson.M{field: bson.M{"$in": include}})
+		}
+		if excludeSet {
+			clauses = append(clauses, bson.M{field: bson.M{"$nin": exclude}})
+		}
+	}
+	addExists := func(field string, exists *bool) {
+		if exists != nil {
+			clauses = append(clauses, bson.M{field + ".0": bson.M{"$exists": *exists}})
+		}
+	}
+
+	assigneeIds, err := objectIds(filter.AssigneeIds, "member")
+	if err != nil {
+		return nil, err
+	}
+	excludeAssigneeIds, err := objectIds(filter.ExcludeAssigneeIds, "member")
+	if err != nil {
+		return nil, err
+	}
+	addIn("assigneeIds", assigneeIds, excludeAssigneeIds, len(filter.AssigneeIds) > 0, len(filter.ExcludeAssigneeIds) > 0)
+	if filter.AssigneeId != `` {
+		assigneeId, err := objectIds([]string{filter.AssigneeId}, "member")
+		if err != nil {
+			return nil, err
+		}
+		addIn("assigneeIds", assigneeId, nil, true, false)
+	}
+
+	memberIds := func(names []string) []primitive.ObjectID {
+		ids := []primitive.ObjectID{}
+		for _, mem := range board.Members {
+			if containsFold(names, mem.Name) {
+				ids = append(ids, mem.Id)
+			}
+		}
+		return ids
+	}
+	addIn("assigneeIds", memberIds(filter.AssigneeNames), memberIds(filter.ExcludeAssigneeNames),
+		len(filter.AssigneeNames) > 0, len(filter.ExcludeAssigneeNames) > 0)
+	addExists("assigneeIds", filter.HasAssignees)
+
+	labelIds := func(names []string) []primitive.ObjectID {
+		ids := []primitive.ObjectID{}
+		for _, l := range board.Labels {
+			if containsFold(names, l.Name) {
+				ids = append(ids, l.Id)
+			}
+		}
+		return ids
+	}
+	addIn("labelIds", labelIds(filter.LabelNames), labelIds(filter.ExcludeLabelNames),
+		len(filter.LabelNames) > 0, len(filter.ExcludeLabelNames) > 0)
+	addExists("labelIds", filter.HasLabels)
+
+	if len(filter.ColumnNames) > 0 || len(filter.ExcludeColumnNames) > 0 {
+		cursor, err := m.columnCol.Find(ctx, bson.M{"_id": bson.M{"$in": board.ColumnIds}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get columns: %w", err)
+		}
+		var columns []column
+		err = cursor.All(ctx, &columns)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode columns: %w", err)
+		}
+
+		columnIds := func(names []string) []primitive.ObjectID {
+			ids := []primitive.ObjectID{}
+			for _, c := range columns {
+				if containsFold(names, c.Name) {
+					ids = append(ids, c.Id)
+				}
+			}
+			return ids
+		}
+		addIn("columnId", columnIds(filter.ColumnNames), columnIds(filter.ExcludeColumnNames),
+			len(filter.ColumnNames) > 0, len(filter.ExcludeColumnNames) > 0)
+	}
+
+	if filter.DueBefore != nil {
+		clauses = append(clauses, bson.M{"dueDate": bson.M{"$lt": *filter.DueBefore}})
+	}
+	if filter.DueAfter != nil {
+		clauses = append(clauses, bson.M{"dueDate": bson.M{"$gt": *filter.DueAfter}})
+	}
+	if filter.HasDueDate != nil {
+		if *filter.HasDueDate {
+			clauses = append(clauses, bson.M{"dueDate": bson.M{"$ne": nil}})
+		} else {
+			clauses = append(clauses, bson.M{"dueDate": nil})
+		}
+	}
+
+	for _, word := range filter.Words {
+		clauses = append(clauses, bson.M{"$or": wordMatch(word)})
+	}
+	for _, word := range filter.ExcludeWords {
+		clauses = append(clauses, bson.M{"$nor": wordMatch(word)})
+	}
+
+	if len(clauses) == 0 {
+		return bson.M{}, nil
+	}
+	return bson.M{"$and": clauses}, nil
+}
+
+// wordMatch matches cards with the word anywhere in their title or description.
+func wordMatch(word string) []bson.M {
+	pattern := primitive.Regex{Pattern: regexp.QuoteMeta(word), Options: "i"}
+	return []bson.M{
+		{"title": pattern},
+		{"description": pattern},
+	}
+}
+
+func objectIds(idStrs []string, typ string) ([]primitive.ObjectID, error) {
+	ids := make([]primitive.ObjectID, 0, len(idStrs))
+	for _, idStr := range idStrs {
+		id, err := primitive.ObjectIDFromHex(idStr)
+		if err != nil {
+			return nil, store.NewBadRequestError(fmt.Sprintf("invalid %s id: %s", typ, idStr))
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package mdb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/danharasymiw/danban/server/store"
+)
+
+func (m *MongoDb) AddLabel(ctx context.Context, boardName string, labelDTO *store.Label) error {
+	newLabel := label{
+		Id:    primitive.NewObjectID(),
+		Name:  labelDTO.Name,
+		Color: labelDTO.Color,
+	}
+
+	result, err := m.boardCol.UpdateOne(
+		ctx,
+		bson.M{"name": boardName},
+		bson.M{"$push": bson.M{"labels": newLabel}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add label to board: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return store.NewNotFoundError("board", boardName)
+	}
+
+	labelDTO.Id = newLabel.Id.Hex()
+	return nil
+}
+
+func (m *MongoDb) DeleteLabel(ctx context.Context, boardName, labelIdStr string) error {
+	labelId, err := primitive.ObjectIDFromHex(labelIdStr)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid label id: %s", labelIdStr))
+	}
+
+	var board board
+	err = m.boardCol.FindOneAndUpdate(
+		ctx,
+		bson.M{"name": boardName},
+		bson.M{"$pull": bson.M{"labels": bson.M{"_id": labelId}}},
+	).Decode(&board)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return store.NewNotFoundError("board", boardName)
+		}
+		return fmt.Errorf("failed to remove label from board: %w", err)
+	}
+
+	// Cards only hold a reference to the label, so drop it from any card on the board.
+	_, err = m.cardCol.UpdateMany(
+		ctx,
+		bson.M{"columnId": bson.M{"$in": board.ColumnIds}},
+		bson.M{"$pull": bson.M{"labelIds": labelId}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove label from cards: %w", err)
+	}
+	return nil
+}
+
+func (m *MongoDb) GetLabels(ctx context.Context, boardName string) ([]*store.Label, error) {
+	var board board
+	err := m.boardCol.FindOne(ctx, bson.M{"name": boardName}).Decode(&board)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, store.NewNotFoundError("board", boardName)
+		}
+		return nil, fmt.Errorf("unexpected error getting board labels: %w", err)
+	}
+
+	return toStoreLabels(board.Labels), nil
+}
+
+func toStoreLabels(labels []label) []*store.Label {
+	storeLabels := make([]*store.Label, 0, len(labels))
+	for _, l := range labels {
+		storeLabels = append(storeLabels, &store.Label{
+			Id:    l.Id.Hex(),
+			Name:  l.Name,
+			Color: l.Color,
+		})
+	}
+	return storeLabels
+}
+
+// cardLabels resolves a card's label references against its board's palette, skipping any that no longer exist.
+func cardLabels(labelIds []primitive.ObjectID, palette []*store.Label) []*store.Label {
+	labels := make([]*store.Label, 0, len(labelIds))
+	for _, id := range labelIds {
+		for _, l := range palette {
+			if l.Id == id.Hex() {
+				labels = append(labels, l)
+				break
+			}
+		}
+	}
+	return labels
+}
+
+func labelIdsFromStore(labels []*store.Label) ([]primitive.ObjectID, error) {
+	ids := make([]primitive.ObjectID, 0, len(labels))
+	for _, l := range labels {
+		id, err := primitive.ObjectIDFromHex(l.Id)
+		if err != nil {
+			return nil, store.NewBadRequestError(fmt.Sprintf("invalid label id: %s", l.Id))
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,36 @@
+package mdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TryLock keeps one lock document per name, which the holder owns until it expires.
+func (m *MongoDb) TryLock(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	_, err := m.lockCol.UpdateOne(
+		ctx,
+		bson.M{
+			"_id": name,
+			"$or": []bson.M{
+				{"holder": holder},
+				{"expiresAt": bson.M{"$lt": now}},
+			},
+		},
+		bson.M{"$set": bson.M{"holder": holder, "expiresAt": now.Add(ttl)}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		// Someone else holds the lock, so the filter missed it and the upsert ran into their lock instead.
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to take lock %s: %w", name, err)
+	}
+	return true, nil
+}
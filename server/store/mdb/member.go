@@ -0,0 +1,114 @@
+package mdb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/danharasymiw/danban/server/store"
+)
+
+func (m *MongoDb) AddMember(ctx context.Context, boardName string, memberDTO *store.Member) error {
+	newMember := member{
+		Id:   primitive.NewObjectID(),
+		Name: memberDTO.Name,
+	}
+
+	result, err := m.boardCol.UpdateOne(
+		ctx,
+		bson.M{"name": boardName},
+		bson.M{"$push": bson.M{"members": newMember}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add member to board: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return store.NewNotFoundError("board", boardName)
+	}
+
+	memberDTO.Id = newMember.Id.Hex()
+	return nil
+}
+
+func (m *MongoDb) DeleteMember(ctx context.Context, boardName, memberIdStr string) error {
+	memberId, err := primitive.ObjectIDFromHex(memberIdStr)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid member id: %s", memberIdStr))
+	}
+
+	var board board
+	err = m.boardCol.FindOneAndUpdate(
+		ctx,
+		bson.M{"name": boardName},
+		bson.M{"$pull": bson.M{"members": bson.M{"_id": memberId}}},
+	).Decode(&board)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return store.NewNotFoundError("board", boardName)
+		}
+		return fmt.Errorf("failed to remove member from board: %w", err)
+	}
+
+	_, err = m.cardCol.UpdateMany(
+		ctx,
+		bson.M{"columnId": bson.M{"$in": board.ColumnIds}},
+		bson.M{"$pull": bson.M{"assigneeIds": memberId}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to unassign member from cards: %w", err)
+	}
+	return nil
+}
+
+func (m *MongoDb) GetMembers(ctx context.Context, boardName string) ([]*store.Member, error) {
+	var board board
+	err := m.boardCol.FindOne(ctx, bson.M{"name": boardName}).Decode(&board)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, store.NewNotFoundError("board", boardName)
+		}
+		return nil, fmt.Errorf("unexpected error getting board members: %w", err)
+	}
+
+	return toStoreMembers(board.Members), nil
+}
+
+func toStoreMembers(members []member) []*store.Member {
+	storeMembers := make([]*store.Member, 0, len(members))
+	for _, mem := range members {
+		storeMembers = append(storeMembers, &store.Member{
+			Id:   mem.Id.Hex(),
+			Name: mem.Name,
+		})
+	}
+	return storeMembers
+}
+
+// cardAssignees resolves a card's assignee references against its board's members, skipping any that were removed.
+func cardAssignees(assigneeIds []primitive.ObjectID, members []*store.Member) []*store.Member {
+	assignees := make([]*store.Member, 0, len(assigneeIds))
+	for _, id := range assigneeIds {
+		for _, mem := range members {
+			if mem.Id == id.Hex() {
+				assignees = append(assignees, mem)
+				break
+			}
+		}
+	}
+	return assignees
+}
+
+func memberIdsFromStore(members []*store.Member) ([]primitive.ObjectID, error) {
+	ids := make([]primitive.ObjectID, 0, len(members))
+	for _, mem := range members {
+		id, err := primitive.ObjectIDFromHex(mem.Id)
+		if err != nil {
+			return nil, store.NewBadRequestError(fmt.Sprintf("invalid member id: %s", mem.Id))
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
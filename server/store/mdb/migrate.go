@@ -0,0 +1,167 @@
+package mdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/danharasymiw/danban/server/logger"
+)
+
+// uniqueBoardNames gives boards a unique index on their name, since boards are looked up by it. Boards used to be made
+// without checking their name was free, so any that share a name with an older board are renamed first, see
+// renameDuplicateBoards.
+func (m *MongoDb) uniqueBoardNames(ctx context.Context) error {
+	err := m.renameDuplicateBoards(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.boardCol.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to index board names: %w", err)
+	}
+	return nil
+}
+
+// renameDuplicateBoards renames boards that share a name with an older board to the name with a number on the end,
+// like team-2. The oldest board keeps the name, and the renames are logged so anyone missing a board can find it.
+func (m *MongoDb) renameDuplicateBoards(ctx context.Context) error {
+	cursor, err := m.boardCol.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+		{{Key: "$group", Value: bson.M{"_id": "$name", "ids": bson.M{"$push": "$_id"}}}},
+		{{Key: "$match", Value: bson.M{"ids.1": bson.M{"$exists": true}}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to find boards with the same name: %w", err)
+	}
+	var duplicates []struct {
+		Name string               `bson:"_id"`
+		Ids  []primitive.ObjectID `bson:"ids"`
+	}
+	if err := cursor.All(ctx, &duplicates); err != nil {
+		return fmt.Errorf("failed to decode boards with the same name: %w", err)
+	}
+
+	for _, duplicate := range duplicates {
+		suffix := 2
+		for _, id := range duplicate.Ids[1:] {
+			var newName string
+			for {
+				newName = fmt.Sprintf("%s-%d", duplicate.Name, suffix)
+				suffix++
+				count, err := m.boardCol.CountDocuments(ctx, bson.M{"name": newName})
+				if err != nil {
+					return fmt.Errorf("failed to check board name: %w", err)
+				}
+				if count == 0 {
+					break
+				}
+			}
+
+			_, err := m.boardCol.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"name": newName}})
+			if err != nil {
+				return fmt.Errorf("failed to rename board: %w", err)
+			}
+			logger.New(ctx).Warnf("Renamed board %s (%s) to %s, since an older board has the same name", duplicate.Name, id.Hex(), newName)
+		}
+	}
+	return nil
+}
+
+// numberOldCards numbers cards from before boards handed out numbers, in column and card order. It runs when the
+// server starts rather than when boards are looked at, so looking at a board never changes it. Numbered cards are
+// left alone, so running it again only numbers cards it missed.
+func (m *MongoDb) numberOldCards(ctx context.Context) error {
+	// With more than one server starting at once, only one of them needs to do it.
+	ok, err := m.TryLock(ctx, "numberOldCards", primitive.NewObjectID().Hex(), 5*time.Minute)
+	if err != nil || !ok {
+		return err
+	}
+
+	columnIds, err := m.cardCol.Distinct(ctx, "columnId", bson.M{"number": bson.M{"$exists": false}})
+	if err != nil {
+		return fmt.Errorf("failed to find unnumbered cards: %w", err)
+	}
+	if len(columnIds) == 0 {
+		return nil
+	}
+
+	cursor, err := m.boardCol.Find(
+		ctx,
+		bson.M{"columnIds": bson.M{"$in": columnIds}},
+		options.Find().SetProjection(bson.M{"columnIds": 1}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to find boards with unnumbered cards: %w", err)
+	}
+	var boards []board
+	if err := cursor.All(ctx, &boards); err != nil {
+		return fmt.Errorf("failed to decode boards with unnumbered cards: %w", err)
+	}
+
+	for _, b := range boards {
+		err = m.numberBoardCards(ctx, b)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MongoDb) numberBoardCards(ctx context.Context, b board) error {
+	cursor, err := m.columnCol.Find(
+		ctx,
+		bson.M{"_id": bson.M{"$in": b.ColumnIds}},
+		options.Find().SetSort(bson.M{"index": 1}).SetProjection(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get board columns: %w", err)
+	}
+	var columns []column
+	if err := cursor.All(ctx, &columns); err != nil {
+		return fmt.Errorf("failed to decode board columns: %w", err)
+	}
+
+	var unnumbered []card
+	for _, col := range columns {
+		cursor, err := m.cardCol.Find(
+			ctx,
+			bson.M{"columnId": col.Id, "number": bson.M{"$exists": false}},
+			options.Find().SetSort(bson.M{"index": 1}).SetProjection(bson.M{"_id": 1}),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to get unnumbered cards: %w", err)
+		}
+		var cards []card
+		if err := cursor.All(ctx, &cards); err != nil {
+			return fmt.Errorf("failed to decode unnumbered cards: %w", err)
+		}
+		unnumbered = append(unnumbered, cards...)
+	}
+	if len(unnumbered) == 0 {
+		return nil
+	}
+
+	number, err := m.allocateCardNumbers(ctx, bson.M{"_id": b.Id}, len(unnumbered))
+	if err != nil {
+		return err
+	}
+
+	for _, c := range unnumbered {
+		_, err := m.cardCol.UpdateOne(ctx, bson.M{"_id": c.Id, "number": bson.M{"$exists": false}}, bson.M{"$set": bson.M{"number": number}})
+		if err != nil {
+			return fmt.Errorf("failed to number card: %w", err)
+		}
+		number++
+	}
+	return nil
+}
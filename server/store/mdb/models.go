@@ -1,25 +1,154 @@
 package mdb
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 type board struct {
-	Id        primitive.ObjectID   `bson:"_id,omitempty"`
-	Name      string               `bson:"name"`
-	ColumnIds []primitive.ObjectID `bson:"columnIds,omitempty"`
-	Columns   []column             `bson:"columns,omitempty"` // This is just here for the aggregation, never stored
+	Id           primitive.ObjectID   `bson:"_id,omitempty"`
+	Name         string               `bson:"name"`
+	ColumnIds    []primitive.ObjectID `bson:"columnIds,omitempty"`
+	Columns      []column             `bson:"columns,omitempty"` // This is just here for the aggregation, never stored
+	Labels       []label              `bson:"labels,omitempty"`
+	Members      []member             `bson:"members,omitempty"`
+	Views        []view               `bson:"views,omitempty"`
+	Roles        []boardRole          `bson:"roles,omitempty"`
+	ShareLinks   []shareLink          `bson:"shareLinks,omitempty"`
+	PasswordHash string               `bson:"passwordHash,omitempty"`
+	// Cleared once the password has been set, see store.Board.
+	PasswordSetupHash string `bson:"passwordSetupHash,omitempty"`
+	// Cleared once the board has an owner.
+	ClaimHash       string `bson:"claimHash,omitempty"`
+	LastCardNumber  int    `bson:"lastCardNumber"` // The last number handed out to a card on the board
+	AutoArchiveDays int    `bson:"autoArchiveDays,omitempty"`
+}
+
+type boardRole struct {
+	UserId primitive.ObjectID `bson:"userId"`
+	Role   string             `bson:"role"`
+}
+
+type shareLink struct {
+	Id        primitive.ObjectID `bson:"_id,omitempty"`
+	Name      string             `bson:"name"`
+	TokenHash string             `bson:"tokenHash"`
+	CreatedAt time.Time          `bson:"createdAt"`
 }
 
 type column struct {
+	Id        primitive.ObjectID `bson:"_id,omitempty"`
+	Index     int                `bson:"index"`
+	Name      string             `bson:"name"`
+	Done      bool               `bson:"done"`
+	Cards     []card             `bson:"cards,omitempty"`     // This is just here for the aggregation, never stored
+	CardCount int                `bson:"cardCount,omitempty"` // This is just here for the aggregation, never stored
+}
+
+type card struct {
+	Id          primitive.ObjectID   `bson:"_id,omitempty"`
+	Number      int                  `bson:"number,omitempty"`
+	Index       int                  `bson:"index"`
+	Title       string               `bson:"title"`
+	Description string               `bson:"description"`
+	ColumnId    primitive.ObjectID   `bson:"columnId"`
+	LabelIds    []primitive.ObjectID `bson:"labelIds,omitempty"`
+	AssigneeIds []primitive.ObjectID `bson:"assigneeIds,omitempty"`
+	StartDate   *time.Time           `bson:"startDate,omitempty"`
+	DueDate     *time.Time           `bson:"dueDate,omitempty"`
+	Lane        string               `bson:"lane,omitempty"`
+	ArchivedAt  *time.Time           `bson:"archivedAt,omitempty"`
+	MovedAt     time.Time            `bson:"movedAt,omitempty"` // When the card was put in its column, unset on older cards
+	Checklists  []checklist          `bson:"checklists,omitempty"`
+	Attachments []attachment         `bson:"attachments,omitempty"`
+}
+
+type attachment struct {
+	Id           primitive.ObjectID `bson:"_id,omitempty"`
+	Name         string             `bson:"name"`
+	ContentType  string             `bson:"contentType"`
+	Size         int64              `bson:"size"`
+	HasThumbnail bool               `bson:"hasThumbnail"`
+	CreatedAt    time.Time          `bson:"createdAt"`
+}
+
+type checklist struct {
 	Id    primitive.ObjectID `bson:"_id,omitempty"`
-	Index int                `bson:"index"`
 	Name  string             `bson:"name"`
-	Cards []card             `bson:"cards,omitempty"` // This is just here for the aggregation, never stored
+	Items []checklistItem    `bson:"items"`
 }
 
-type card struct {
-	Id          primitive.ObjectID `bson:"_id,omitempty"`
-	Index       int                `bson:"index"`
-	Title       string             `bson:"title"`
-	Description string             `bson:"description"`
-	ColumnId    primitive.ObjectID `bson:"columnId"`
+type checklistItem struct {
+	Id   primitive.ObjectID `bson:"_id,omitempty"`
+	Text string             `bson:"text"`
+	Done bool               `bson:"done"`
+}
+
+type label struct {
+	Id    primitive.ObjectID `bson:"_id,omitempty"`
+	Name  string             `bson:"name"`
+	Color string             `bson:"color"`
+}
+
+type view struct {
+	Id                 primitive.ObjectID   `bson:"_id,omitempty"`
+	Name               string               `bson:"name"`
+	Filter             string               `bson:"filter,omitempty"`
+	Assignee           string               `bson:"assignee,omitempty"`
+	Sort               string               `bson:"sort,omitempty"`
+	Lanes              string               `bson:"lanes,omitempty"`
+	CollapsedColumnIds []primitive.ObjectID `bson:"collapsedColumnIds,omitempty"`
+}
+
+type member struct {
+	Id   primitive.ObjectID `bson:"_id,omitempty"`
+	Name string             `bson:"name"`
+}
+
+type comment struct {
+	Id         primitive.ObjectID `bson:"_id,omitempty"`
+	CardId     primitive.ObjectID `bson:"cardId"`
+	AuthorId   primitive.ObjectID `bson:"authorId"`
+	AuthorName string             `bson:"authorName"`
+	Body       string             `bson:"body"`
+	CreatedAt  time.Time          `bson:"createdAt"`
+	EditedAt   *time.Time         `bson:"editedAt,omitempty"`
+}
+
+// cardLink is stored in one direction only, blocked-by and duplicated-by are stored as their inverse.
+type cardLink struct {
+	Id         primitive.ObjectID `bson:"_id,omitempty"`
+	FromCardId primitive.ObjectID `bson:"fromCardId"`
+	ToCardId   primitive.ObjectID `bson:"toCardId"`
+	Type       string             `bson:"type"`
+}
+
+type user struct {
+	Id           primitive.ObjectID `bson:"_id,omitempty"`
+	Email        string             `bson:"email"`
+	Name         string             `bson:"name"`
+	PasswordHash string             `bson:"passwordHash,omitempty"`
+	CreatedAt    time.Time          `bson:"createdAt"`
+	OIDCIssuer   string             `bson:"oidcIssuer,omitempty"`
+	OIDCSubject  string             `bson:"oidcSubject,omitempty"`
+}
+
+type session struct {
+	Id        string             `bson:"_id"`
+	UserId    primitive.ObjectID `bson:"userId"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	ExpiresAt time.Time          `bson:"expiresAt"`
+}
+
+type apiToken struct {
+	Id         primitive.ObjectID `bson:"_id,omitempty"`
+	UserId     primitive.ObjectID `bson:"userId"`
+	Name       string             `bson:"name"`
+	Hash       string             `bson:"hash"`
+	BoardNames []string           `bson:"boardNames"`
+	Write      bool               `bson:"write"`
+	CreatedAt  time.Time          `bson:"createdAt"`
+	ExpiresAt  time.Time          `bson:"expiresAt"`
+	LastUsedAt *time.Time         `bson:"lastUsedAt,omitempty"`
 }
@@ -5,20 +5,28 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
+	"github.com/danharasymiw/danban/server/logger"
 	"github.com/danharasymiw/danban/server/store"
 )
 
 type MongoDb struct {
-	client    *mongo.Client
-	boardCol  *mongo.Collection
-	columnCol *mongo.Collection
-	cardCol   *mongo.Collection
+	client     *mongo.Client
+	boardCol   *mongo.Collection
+	columnCol  *mongo.Collection
+	cardCol    *mongo.Collection
+	commentCol *mongo.Collection
+	linkCol    *mongo.Collection
+	lockCol    *mongo.Collection
+	userCol    *mongo.Collection
+	sessionCol *mongo.Collection
+	tokenCol   *mongo.Collection
 }
 
 const dbName = "danban"
@@ -38,13 +46,103 @@ func New() *MongoDb {
 	boardCol := client.Database(dbName).Collection("boards")
 	columnCol := client.Database(dbName).Collection("columns")
 	cardCol := client.Database(dbName).Collection("cards")
+	commentCol := client.Database(dbName).Collection("comments")
 
-	return &MongoDb{
-		client:    client,
-		boardCol:  boardCol,
-		columnCol: columnCol,
-		cardCol:   cardCol,
+	linkCol := client.Database(dbName).Collection("cardLinks")
+	lockCol := client.Database(dbName).Collection("locks")
+	userCol := client.Database(dbName).Collection("users")
+	sessionCol := client.Database(dbName).Collection("sessions")
+	tokenCol := client.Database(dbName).Collection("apiTokens")
+
+	_, err = commentCol.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "cardId", Value: 1}, {Key: "createdAt", Value: 1}},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = cardCol.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "title", Value: "text"}, {Key: "description", Value: "text"}},
+		Options: options.Index().SetWeights(bson.M{"title": 3, "description": 1}),
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = linkCol.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "fromCardId", Value: 1}}},
+		{Keys: bson.D{{Key: "toCardId", Value: 1}}},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = userCol.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "oidcIssuer", Value: 1}, {Key: "oidcSubject", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"oidcSubject": bson.M{"$exists": true}}),
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// Mongo deletes sessions once they expire.
+	_, err = sessionCol.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// Mongo deletes API tokens once they expire too.
+	_, err = tokenCol.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "userId", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	m := &MongoDb{
+		client:     client,
+		boardCol:   boardCol,
+		columnCol:  columnCol,
+		cardCol:    cardCol,
+		commentCol: commentCol,
+		linkCol:    linkCol,
+		lockCol:    lockCol,
+		userCol:    userCol,
+		sessionCol: sessionCol,
+		tokenCol:   tokenCol,
+	}
+
+	// Carrying on without the index only brings back the chance of two people making the same board at once, which
+	// is better than not starting at all.
+	err = m.uniqueBoardNames(context.TODO())
+	if err != nil {
+		logger.New(context.TODO()).WithError(err).Error("Failed to make board names unique, two boards could end up with the same name")
 	}
+
+	err = m.numberOldCards(context.TODO())
+	if err != nil {
+		panic(err)
+	}
+
+	return m
 }
 
 func (m *MongoDb) GetCardCount(ctx context.Context, columnIdStr string) (int, error) {
@@ -53,7 +151,7 @@ func (m *MongoDb) GetCardCount(ctx context.Context, columnIdStr string) (int, er
 		return 0, store.NewBadRequestError(fmt.Sprintf("invalid column id: %s", columnIdStr))
 	}
 
-	count, err := m.cardCol.CountDocuments(ctx, bson.M{"columnId": columnId})
+	count, err := m.cardCol.CountDocuments(ctx, bson.M{"columnId": columnId, "archivedAt": nil})
 	if err != nil {
 		return 0, fmt.Errorf("failed to count documents in target column: %w", err)
 	}
@@ -71,10 +169,17 @@ func (m *MongoDb) AddCard(ctx context.Context, columnIdStr string, cardTitle str
 		return nil, fmt.Errorf("failed to count documents in target column: %w", err)
 	}
 
+	number, err := m.allocateCardNumbers(ctx, bson.M{"columnIds": columnId}, 1)
+	if err != nil {
+		return nil, err
+	}
+
 	newCard := &card{
 		ColumnId: columnId,
+		Number:   number,
 		Title:    cardTitle,
 		Index:    int(count),
+		MovedAt:  time.Now(),
 	}
 
 	result, err := m.cardCol.InsertOne(ctx, newCard)
@@ -83,19 +188,57 @@ func (m *MongoDb) AddCard(ctx context.Context, columnIdStr string, cardTitle str
 	}
 
 	return &store.Card{
-		Id:    result.InsertedID.(primitive.ObjectID).Hex(),
-		Title: cardTitle,
-		Index: 0,
+		Id:       result.InsertedID.(primitive.ObjectID).Hex(),
+		ColumnId: columnIdStr,
+		Number:   number,
+		Title:    cardTitle,
+		Index:    newCard.Index,
 	}, nil
 }
 
+// allocateCardNumbers atomically reserves count card numbers on the matching board, returning the first of them.
+func (m *MongoDb) allocateCardNumbers(ctx context.Context, boardFilter bson.M, count int) (int, error) {
+	var board board
+	err := m.boardCol.FindOneAndUpdate(
+		ctx,
+		boardFilter,
+		bson.M{"$inc": bson.M{"lastCardNumber": count}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After).SetProjection(bson.M{"lastCardNumber": 1}),
+	).Decode(&board)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, store.NewNotFoundError("board", fmt.Sprint(boardFilter))
+		}
+		return 0, fmt.Errorf("failed to allocate card number: %w", err)
+	}
+	return board.LastCardNumber - count + 1, nil
+}
+
 func (m *MongoDb) EditCard(ctx context.Context, card *store.Card) error {
+	labelIds, err := labelIdsFromStore(card.Labels)
+	if err != nil {
+		return err
+	}
+
+	assigneeIds, err := memberIdsFromStore(card.Assignees)
+	if err != nil {
+		return err
+	}
+
 	updateFields := bson.M{
 		"title":       card.Title,
 		"description": card.Description,
+		"labelIds":    labelIds,
+		"assigneeIds": assigneeIds,
+		"startDate":   card.StartDate,
+		"dueDate":     card.DueDate,
+		"lane":        card.Lane,
 	}
 
 	cardId, err := primitive.ObjectIDFromHex(card.Id)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid card id: %s", card.Id))
+	}
 
 	updateResult, err := m.cardCol.UpdateOne(
 		ctx,
@@ -126,6 +269,9 @@ func (m *MongoDb) MoveCard(ctx context.Context, toColumnIdStr, cardIdStr string,
 		}
 		return fmt.Errorf("error finding card by id %s: %w", cardId, err)
 	}
+	if card.ArchivedAt != nil {
+		return store.NewBadRequestError("restore the card before moving it")
+	}
 
 	toColumnId, err := primitive.ObjectIDFromHex(toColumnIdStr)
 	if err != nil {
@@ -137,8 +283,13 @@ func (m *MongoDb) MoveCard(ctx context.Context, toColumnIdStr, cardIdStr string,
 		return fmt.Errorf("failed to count documents in target column: %w", err)
 	}
 
-	if newIndex < 0 || newIndex > int(count) {
-		newIndex = int(count)
+	maxIndex := int(count)
+	// The card is already counted when it stays in its column.
+	if card.ColumnId == toColumnId {
+		maxIndex--
+	}
+	if newIndex < 0 || newIndex > maxIndex {
+		newIndex = maxIndex
 	}
 
 	if card.ColumnId == toColumnId {
@@ -197,15 +348,17 @@ func (m *MongoDb) MoveCard(ctx context.Context, toColumnIdStr, cardIdStr string,
 			return fmt.Errorf("error shifting card indices in to column: %w", err)
 		}
 	}
+	update := bson.M{
+		"columnId": toColumnId,
+		"index":    newIndex,
+	}
+	if card.ColumnId != toColumnId {
+		update["movedAt"] = time.Now()
+	}
 	_, err = m.cardCol.UpdateOne(
 		ctx,
 		bson.M{"_id": cardId},
-		bson.M{
-			"$set": bson.M{
-				"columnId": toColumnId,
-				"index":    newIndex,
-			},
-		},
+		bson.M{"$set": update},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update card index: %w", err)
@@ -242,6 +395,24 @@ func (m *MongoDb) DeleteCard(ctx context.Context, columnIdStr, cardIdStr string,
 		return fmt.Errorf("no card found while deleting with ID: %v", cardIdStr)
 	}
 
+	_, err = m.commentCol.DeleteMany(ctx, bson.M{"cardId": cardId})
+	if err != nil {
+		return fmt.Errorf("failed to delete card comments: %w", err)
+	}
+
+	_, err = m.linkCol.DeleteMany(ctx, bson.M{"$or": []bson.M{
+		{"fromCardId": cardId},
+		{"toCardId": cardId},
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to delete card links: %w", err)
+	}
+
+	// Archived cards were already taken out of the column's order.
+	if cardIndex < 0 {
+		return nil
+	}
+
 	_, err = m.cardCol.UpdateMany(
 		ctx,
 		bson.M{
@@ -274,24 +445,122 @@ func (m *MongoDb) GetCard(ctx context.Context, cardIdStr string) (*store.Card, e
 		}
 	}
 
+	board, err := m.getBoardByColumnId(ctx, card.ColumnId)
+	if err != nil {
+		return nil, err
+	}
+
+	blocked, err := m.blockedCardIds(ctx, []primitive.ObjectID{cardId})
+	if err != nil {
+		return nil, err
+	}
+
+	storeCard := toStoreCard(&card, toStoreLabels(board.Labels), toStoreMembers(board.Members))
+	storeCard.Blocked = blocked[cardId]
+	return storeCard, nil
+}
+
+// toStoreCard converts a stored card, resolving its references against the board's labels and members.
+func toStoreCard(c *card, labels []*store.Label, members []*store.Member) *store.Card {
 	return &store.Card{
-		Id:          cardIdStr,
-		Title:       card.Title,
-		Description: card.Description,
-		Index:       card.Index,
-	}, nil
+		Id:          c.Id.Hex(),
+		ColumnId:    c.ColumnId.Hex(),
+		Number:      c.Number,
+		Title:       c.Title,
+		Description: c.Description,
+		Index:       c.Index,
+		Labels:      cardLabels(c.LabelIds, labels),
+		Assignees:   cardAssignees(c.AssigneeIds, members),
+		StartDate:   c.StartDate,
+		DueDate:     c.DueDate,
+		Lane:        c.Lane,
+		ArchivedAt:  c.ArchivedAt,
+		Checklists:  toStoreChecklists(c.Checklists),
+		Attachments: toStoreAttachments(c.Attachments),
+	}
 }
 
-func (m *MongoDb) GetCards(ctx context.Context, boardId, columnId, cardId string) ([]*store.Card, error) {
-	return nil, errors.New(`Not implemented`)
+func (m *MongoDb) GetCardByNumber(ctx context.Context, boardName string, number int) (*store.Card, error) {
+	var board board
+	err := m.boardCol.FindOne(ctx, bson.M{"name": boardName}).Decode(&board)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, store.NewNotFoundError("board", boardName)
+		}
+		return nil, fmt.Errorf("unexpected error getting board: %w", err)
+	}
+
+	var card card
+	err = m.cardCol.FindOne(ctx, bson.M{
+		"columnId": bson.M{"$in": board.ColumnIds},
+		"number":   number,
+	}).Decode(&card)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, store.NewNotFoundError("card", store.CardRef(boardName, number))
+		}
+		return nil, fmt.Errorf("unexpected error getting card by number: %w", err)
+	}
+
+	return m.GetCard(ctx, card.Id.Hex())
+}
+
+// getBoardByColumnId finds the board owning the given column, without any of its columns or cards.
+func (m *MongoDb) getBoardByColumnId(ctx context.Context, columnId primitive.ObjectID) (*board, error) {
+	var board board
+	err := m.boardCol.FindOne(ctx, bson.M{"columnIds": columnId}).Decode(&board)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, store.NewNotFoundError("board for column", columnId.Hex())
+		}
+		return nil, fmt.Errorf("unexpected error getting board for column: %w", err)
+	}
+	return &board, nil
+}
+
+func (m *MongoDb) GetColumnBoardName(ctx context.Context, columnIdStr string) (string, error) {
+	columnId, err := primitive.ObjectIDFromHex(columnIdStr)
+	if err != nil {
+		return ``, store.NewBadRequestError(fmt.Sprintf("invalid column id: %s", columnIdStr))
+	}
+
+	board, err := m.getBoardByColumnId(ctx, columnId)
+	if err != nil {
+		return ``, err
+	}
+	return board.Name, nil
 }
 
 func (m *MongoDb) AddColumn(ctx context.Context, boardId, column *store.Column) error {
 	return errors.New(`Not implemented`)
 }
 
-func (m *MongoDb) EditColumn(ctx context.Context, boardId, column *store.Column) error {
-	return errors.New(`Not implemented`)
+func (m *MongoDb) EditColumn(ctx context.Context, boardName string, column *store.Column) error {
+	columnId, err := primitive.ObjectIDFromHex(column.Id)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid column id: %s", column.Id))
+	}
+
+	board, err := m.getBoardByColumnId(ctx, columnId)
+	if err != nil {
+		return err
+	}
+	if board.Name != boardName {
+		return store.NewNotFoundError("column", column.Id)
+	}
+
+	_, err = m.columnCol.UpdateOne(
+		ctx,
+		bson.M{"_id": columnId},
+		bson.M{"$set": bson.M{
+			"name": column.Name,
+			"done": column.Done,
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update column: %w", err)
+	}
+	return nil
 }
 
 func (m *MongoDb) MoveColumn(ctx context.Context, boardId, columnId string, index uint8) error {
@@ -321,13 +590,14 @@ func (m *MongoDb) GetColumn(ctx context.Context, columnIdStr string) (*store.Col
 		Id:    columnIdStr,
 		Name:  column.Name,
 		Index: column.Index,
+		Done:  column.Done,
 	}, nil
 }
 
 func (m *MongoDb) GetColumns(ctx context.Context, boardName string) ([]*store.Column, error) {
 	pipeline := mongo.Pipeline{
-		{{"$match", bson.M{"name": boardName}}},
-		{{"$lookup", bson.M{
+		{{Key: "$match", Value: bson.M{"name": boardName}}},
+		{{Key: "$lookup", Value: bson.M{
 			"from":         "columns",
 			"localField":   "columnIds",
 			"foreignField": "_id",
@@ -355,6 +625,7 @@ func (m *MongoDb) GetColumns(ctx context.Context, boardName string) ([]*store.Co
 			Id:    column.Id.Hex(),
 			Name:  column.Name,
 			Index: column.Index,
+			Done:  column.Done,
 		})
 	}
 	return columns, nil
@@ -368,45 +639,72 @@ func (m *MongoDb) AddBoard(ctx context.Context, boardDTO *store.Board) error {
 	defer session.EndSession(ctx)
 
 	err = mongo.WithSession(ctx, session, func(sc mongo.SessionContext) error {
+		// Ids and numbers are picked up front so the board can go in first, since its unique name is what stops two
+		// people making the same board at once. Losing that race shouldn't leave columns and cards behind.
 		var columnIds []primitive.ObjectID
+		var lastCardNumber int
 		for _, col := range boardDTO.Columns {
+			colId := primitive.NewObjectID()
+			col.Id = colId.Hex()
+			columnIds = append(columnIds, colId)
+			for _, c := range col.Cards {
+				lastCardNumber++
+				c.Number = lastCardNumber
+			}
+		}
+
+		roles, err := boardRolesFromStore(boardDTO.Roles)
+		if err != nil {
+			return err
+		}
+
+		newBoard := &board{
+			Name:              boardDTO.Name,
+			ColumnIds:         columnIds,
+			LastCardNumber:    lastCardNumber,
+			Roles:             roles,
+			PasswordHash:      boardDTO.PasswordHash,
+			PasswordSetupHash: boardDTO.PasswordSetupHash,
+			ClaimHash:         boardDTO.ClaimHash,
+		}
+
+		_, err = m.boardCol.InsertOne(sc, newBoard)
+		if err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				return store.NewBadRequestError(fmt.Sprintf("there's already a board called %s", boardDTO.Name))
+			}
+			return fmt.Errorf("could not insert board: %v", err)
+		}
+
+		for i, col := range boardDTO.Columns {
 			newColumn := &column{
+				Id:    columnIds[i],
 				Name:  col.Name,
 				Index: col.Index,
+				Done:  col.Done,
 			}
-			colRes, err := m.columnCol.InsertOne(sc, newColumn)
+			_, err := m.columnCol.InsertOne(sc, newColumn)
 			if err != nil {
 				return fmt.Errorf("could not insert column: %v", err)
 			}
-			colId := colRes.InsertedID.(primitive.ObjectID)
-			col.Id = colId.Hex()
-			columnIds = append(columnIds, colId)
 
 			for _, c := range col.Cards {
 				newCard := &card{
+					Number:      c.Number,
 					Title:       c.Title,
 					Description: c.Description,
 					Index:       c.Index,
-					ColumnId:    colId,
+					ColumnId:    columnIds[i],
 				}
 				cardRes, err := m.cardCol.InsertOne(sc, newCard)
 				if err != nil {
 					return fmt.Errorf("could not insert card: %v", err)
 				}
 				c.Id = cardRes.InsertedID.(primitive.ObjectID).Hex()
+				c.ColumnId = col.Id
 			}
 		}
 
-		newBoard := &board{
-			Name:      boardDTO.Name,
-			ColumnIds: columnIds,
-		}
-
-		_, err := m.boardCol.InsertOne(sc, newBoard)
-		if err != nil {
-			return fmt.Errorf("could not insert board: %v", err)
-		}
-
 		return nil
 	})
 
@@ -421,7 +719,26 @@ func (m *MongoDb) DeleteBoard(ctx context.Context, boardId string) error {
 	return errors.New(`Not implemented`)
 }
 
-func (m *MongoDb) GetBoard(ctx context.Context, name string) (*store.Board, error) {
+func (m *MongoDb) SetAutoArchiveDays(ctx context.Context, boardName string, days int) error {
+	result, err := m.boardCol.UpdateOne(
+		ctx,
+		bson.M{"name": boardName},
+		bson.M{"$set": bson.M{"autoArchiveDays": days}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set board auto archive days: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return store.NewNotFoundError("board", boardName)
+	}
+	return nil
+}
+
+func (m *MongoDb) GetBoard(ctx context.Context, name string, filter *store.CardFilter) (*store.Board, error) {
+	cardMatch, err := m.cardFilterMatch(ctx, name, filter)
+	if err != nil {
+		return nil, err
+	}
 
 	pipeline := []bson.M{
 		{
@@ -442,14 +759,31 @@ func (m *MongoDb) GetBoard(ctx context.Context, name string) (*store.Board, erro
 		},
 		{
 			"$lookup": bson.M{
-				"from":         "cards",
-				"localField":   "columns._id",
-				"foreignField": "columnId",
-				"as":           "columns.cards",
+				"from": "cards",
+				"let":  bson.M{"columnId": "$columns._id"},
+				"pipeline": []bson.M{
+					{"$match": bson.M{"$expr": bson.M{"$eq": []string{"$columnId", "$$columnId"}}}},
+					{"$match": bson.M{"archivedAt": nil}},
+					{"$match": cardMatch},
+				},
+				"as": "columns.cards",
+			},
+		},
+		{
+			"$lookup": bson.M{
+				"from": "cards",
+				"let":  bson.M{"columnId": "$columns._id"},
+				"pipeline": []bson.M{
+					{"$match": bson.M{"$expr": bson.M{"$eq": []string{"$columnId", "$$columnId"}}}},
+					{"$match": bson.M{"archivedAt": nil}},
+					{"$count": "count"},
+				},
+				"as": "columns.cardCount",
 			},
 		},
 		{
 			"$addFields": bson.M{
+				"columns.cardCount": bson.M{"$ifNull": []any{bson.M{"$first": "$columns.cardCount.count"}, 0}},
 				"columns.cards": bson.M{
 					"$sortArray": bson.M{
 						"input": "$columns.cards",
@@ -462,10 +796,15 @@ func (m *MongoDb) GetBoard(ctx context.Context, name string) (*store.Board, erro
 		},
 		{
 			"$group": bson.M{
-				"_id":       "$_id",
-				"name":      bson.M{"$first": "$name"},
-				"columnIds": bson.M{"$first": "$columnIds"},
-				"columns":   bson.M{"$push": "$columns"},
+				"_id":             "$_id",
+				"name":            bson.M{"$first": "$name"},
+				"columnIds":       bson.M{"$first": "$columnIds"},
+				"columns":         bson.M{"$push": "$columns"},
+				"labels":          bson.M{"$first": "$labels"},
+				"members":         bson.M{"$first": "$members"},
+				"views":           bson.M{"$first": "$views"},
+				"roles":           bson.M{"$first": "$roles"},
+				"autoArchiveDays": bson.M{"$first": "$autoArchiveDays"},
 			},
 		},
 	}
@@ -488,26 +827,46 @@ func (m *MongoDb) GetBoard(ctx context.Context, name string) (*store.Board, erro
 		return nil, store.NewNotFoundError("board", name)
 	}
 
+	labels := toStoreLabels(result.Labels)
+	members := toStoreMembers(result.Members)
+
+	var cardIds []primitive.ObjectID
+	for _, column := range result.Columns {
+		for _, card := range column.Cards {
+			cardIds = append(cardIds, card.Id)
+		}
+	}
+
+	blocked, err := m.blockedCardIds(ctx, cardIds)
+	if err != nil {
+		return nil, err
+	}
+
 	columns := make([]*store.Column, 0, len(result.Columns))
 	for _, column := range result.Columns {
 		cards := make([]*store.Card, 0, len(column.Cards))
 		for _, card := range column.Cards {
-			cards = append(cards, &store.Card{
-				Id:          card.Id.Hex(),
-				Title:       card.Title,
-				Description: card.Description,
-				Index:       card.Index,
-			})
+			storeCard := toStoreCard(&card, labels, members)
+			storeCard.Blocked = blocked[card.Id]
+			cards = append(cards, storeCard)
 		}
 		columns = append(columns, &store.Column{
-			Id:    column.Id.Hex(),
-			Name:  column.Name,
-			Cards: cards,
+			Id:        column.Id.Hex(),
+			Index:     column.Index,
+			Name:      column.Name,
+			Done:      column.Done,
+			Cards:     cards,
+			CardCount: column.CardCount,
 		})
 	}
 
 	return &store.Board{
-		Name:    result.Name,
-		Columns: columns,
+		Name:            result.Name,
+		Columns:         columns,
+		Labels:          labels,
+		Members:         members,
+		Views:           toStoreViews(result.Views),
+		Roles:           toStoreBoardRoles(result.Roles),
+		AutoArchiveDays: result.AutoArchiveDays,
 	}, nil
 }
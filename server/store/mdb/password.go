@@ -0,0 +1,46 @@
+package mdb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/danharasymiw/danban/server/store"
+)
+
+func (m *MongoDb) GetBoardPasswordHash(ctx context.Context, boardName string) (string, error) {
+	var board board
+	err := m.boardCol.FindOne(
+		ctx,
+		bson.M{"name": boardName},
+		options.FindOne().SetProjection(bson.M{"passwordHash": 1}),
+	).Decode(&board)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return ``, store.NewNotFoundError("board", boardName)
+		}
+		return ``, fmt.Errorf("unexpected error getting board password: %w", err)
+	}
+	return board.PasswordHash, nil
+}
+
+func (m *MongoDb) SetBoardPassword(ctx context.Context, boardName, setupTokenHash, passwordHash string) error {
+	result, err := m.boardCol.UpdateOne(
+		ctx,
+		bson.M{"name": boardName, "passwordSetupHash": setupTokenHash},
+		bson.M{
+			"$set":   bson.M{"passwordHash": passwordHash},
+			"$unset": bson.M{"passwordSetupHash": ""},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set board password: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return store.NewForbiddenError("only the board's creator can set its password, and only once")
+	}
+	return nil
+}
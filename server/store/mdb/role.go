@@ -0,0 +1,131 @@
+package mdb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/danharasymiw/danban/server/store"
+)
+
+func (m *MongoDb) GetBoardRoles(ctx context.Context, boardName string) ([]*store.BoardRole, error) {
+	var board board
+	err := m.boardCol.FindOne(
+		ctx,
+		bson.M{"name": boardName},
+		options.FindOne().SetProjection(bson.M{"roles": 1}),
+	).Decode(&board)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, store.NewNotFoundError("board", boardName)
+		}
+		return nil, fmt.Errorf("unexpected error getting board roles: %w", err)
+	}
+
+	return toStoreBoardRoles(board.Roles), nil
+}
+
+func (m *MongoDb) SetBoardRole(ctx context.Context, boardName string, roleDTO *store.BoardRole) error {
+	userId, err := primitive.ObjectIDFromHex(roleDTO.UserId)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid user id: %s", roleDTO.UserId))
+	}
+
+	// Swap out the user's old role and add the new one in a single update, so a user never ends up with two.
+	result, err := m.boardCol.UpdateOne(
+		ctx,
+		bson.M{"name": boardName},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{
+				"roles": bson.M{"$concatArrays": bson.A{
+					bson.M{"$filter": bson.M{
+						"input": bson.M{"$ifNull": bson.A{"$roles", bson.A{}}},
+						"cond":  bson.M{"$ne": bson.A{"$$this.userId", userId}},
+					}},
+					bson.A{boardRole{UserId: userId, Role: string(roleDTO.Role)}},
+				}},
+			}}},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set board role: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return store.NewNotFoundError("board", boardName)
+	}
+	return nil
+}
+
+func (m *MongoDb) ClaimBoard(ctx context.Context, boardName, claimTokenHash, userIdStr string) error {
+	userId, err := primitive.ObjectIDFromHex(userIdStr)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid user id: %s", userIdStr))
+	}
+
+	result, err := m.boardCol.UpdateOne(
+		ctx,
+		bson.M{
+			"name":      boardName,
+			"claimHash": claimTokenHash,
+			"$or":       bson.A{bson.M{"roles": bson.M{"$exists": false}}, bson.M{"roles": bson.M{"$size": 0}}},
+		},
+		bson.M{
+			"$set":   bson.M{"roles": bson.A{boardRole{UserId: userId, Role: string(store.RoleOwner)}}},
+			"$unset": bson.M{"claimHash": ""},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to claim board: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return store.NewForbiddenError("only the board's creator can take ownership of it, and only while nobody owns it")
+	}
+	return nil
+}
+
+func (m *MongoDb) DeleteBoardRole(ctx context.Context, boardName, userIdStr string) error {
+	userId, err := primitive.ObjectIDFromHex(userIdStr)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid user id: %s", userIdStr))
+	}
+
+	result, err := m.boardCol.UpdateOne(
+		ctx,
+		bson.M{"name": boardName},
+		bson.M{"$pull": bson.M{"roles": bson.M{"userId": userId}}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove board role: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return store.NewNotFoundError("board", boardName)
+	}
+	return nil
+}
+
+func toStoreBoardRoles(roles []boardRole) []*store.BoardRole {
+	storeRoles := make([]*store.BoardRole, 0, len(roles))
+	for _, role := range roles {
+		storeRoles = append(storeRoles, &store.BoardRole{
+			UserId: role.UserId.Hex(),
+			Role:   store.Role(role.Role),
+		})
+	}
+	return storeRoles
+}
+
+func boardRolesFromStore(roles []*store.BoardRole) ([]boardRole, error) {
+	mongoRoles := make([]boardRole, 0, len(roles))
+	for _, role := range roles {
+		userId, err := primitive.ObjectIDFromHex(role.UserId)
+		if err != nil {
+			return nil, store.NewBadRequestError(fmt.Sprintf("invalid user id: %s", role.UserId))
+		}
+		mongoRoles = append(mongoRoles, boardRole{UserId: userId, Role: string(role.Role)})
+	}
+	return mongoRoles, nil
+}
@@ -0,0 +1,85 @@
+package mdb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/danharasymiw/danban/server/store"
+)
+
+// SearchCards finds the board's cards matching the query, best matches first. The text index only matches
+// whole words, so it's topped up with cards whose title contains the query to handle partially typed words.
+func (m *MongoDb) SearchCards(ctx context.Context, boardName, query string, limit int) ([]*store.Card, error) {
+	var board board
+	err := m.boardCol.FindOne(ctx, bson.M{"name": boardName}).Decode(&board)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, store.NewNotFoundError("board", boardName)
+		}
+		return nil, fmt.Errorf("unexpected error getting board: %w", err)
+	}
+
+	cursor, err := m.cardCol.Find(
+		ctx,
+		bson.M{
+			"columnId": bson.M{"$in": board.ColumnIds},
+			"$text":    bson.M{"$search": query},
+		},
+		options.Find().
+			SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}}).
+			SetSort(bson.M{"score": bson.M{"$meta": "textScore"}}).
+			SetLimit(int64(limit)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search cards: %w", err)
+	}
+
+	var cards []card
+	err = cursor.All(ctx, &cards)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode searched cards: %w", err)
+	}
+
+	if len(cards) < limit {
+		found := make([]primitive.ObjectID, 0, len(cards))
+		for _, c := range cards {
+			found = append(found, c.Id)
+		}
+
+		cursor, err = m.cardCol.Find(
+			ctx,
+			bson.M{
+				"columnId": bson.M{"$in": board.ColumnIds},
+				"_id":      bson.M{"$nin": found},
+				"title":    primitive.Regex{Pattern: regexp.QuoteMeta(query), Options: "i"},
+			},
+			options.Find().SetLimit(int64(limit-len(cards))),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search card titles: %w", err)
+		}
+
+		var titleMatches []card
+		err = cursor.All(ctx, &titleMatches)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode searched cards: %w", err)
+		}
+		cards = slices.Concat(cards, titleMatches)
+	}
+
+	labels := toStoreLabels(board.Labels)
+	members := toStoreMembers(board.Members)
+
+	storeCards := make([]*store.Card, 0, len(cards))
+	for _, c := range cards {
+		storeCards = append(storeCards, toStoreCard(&c, labels, members))
+	}
+	return storeCards, nil
+}
@@ -0,0 +1,83 @@
+package mdb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/danharasymiw/danban/server/store"
+)
+
+func (m *MongoDb) AddShareLink(ctx context.Context, boardName string, linkDTO *store.ShareLink) error {
+	newLink := shareLink{
+		Id:        primitive.NewObjectID(),
+		Name:      linkDTO.Name,
+		TokenHash: linkDTO.TokenHash,
+		CreatedAt: linkDTO.CreatedAt,
+	}
+
+	result, err := m.boardCol.UpdateOne(
+		ctx,
+		bson.M{"name": boardName},
+		bson.M{"$push": bson.M{"shareLinks": newLink}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add share link to board: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return store.NewNotFoundError("board", boardName)
+	}
+
+	linkDTO.Id = newLink.Id.Hex()
+	return nil
+}
+
+func (m *MongoDb) DeleteShareLink(ctx context.Context, boardName, linkIdStr string) error {
+	linkId, err := primitive.ObjectIDFromHex(linkIdStr)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid share link id: %s", linkIdStr))
+	}
+
+	result, err := m.boardCol.UpdateOne(
+		ctx,
+		bson.M{"name": boardName, "shareLinks._id": linkId},
+		bson.M{"$pull": bson.M{"shareLinks": bson.M{"_id": linkId}}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke share link: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return store.NewNotFoundError("share link", linkIdStr)
+	}
+	return nil
+}
+
+func (m *MongoDb) GetShareLinks(ctx context.Context, boardName string) ([]*store.ShareLink, error) {
+	var board board
+	err := m.boardCol.FindOne(
+		ctx,
+		bson.M{"name": boardName},
+		options.FindOne().SetProjection(bson.M{"shareLinks": 1}),
+	).Decode(&board)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, store.NewNotFoundError("board", boardName)
+		}
+		return nil, fmt.Errorf("unexpected error getting share links: %w", err)
+	}
+
+	links := make([]*store.ShareLink, 0, len(board.ShareLinks))
+	for _, link := range board.ShareLinks {
+		links = append(links, &store.ShareLink{
+			Id:        link.Id.Hex(),
+			Name:      link.Name,
+			TokenHash: link.TokenHash,
+			CreatedAt: link.CreatedAt,
+		})
+	}
+	return links, nil
+}
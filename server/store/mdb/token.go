@@ -0,0 +1,126 @@
+package mdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/danharasymiw/danban/server/store"
+)
+
+func (m *MongoDb) AddAPIToken(ctx context.Context, tokenDTO *store.APIToken) error {
+	userId, err := primitive.ObjectIDFromHex(tokenDTO.UserId)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid user id: %s", tokenDTO.UserId))
+	}
+
+	result, err := m.tokenCol.InsertOne(ctx, apiToken{
+		UserId:     userId,
+		Name:       tokenDTO.Name,
+		Hash:       tokenDTO.Hash,
+		BoardNames: tokenDTO.BoardNames,
+		Write:      tokenDTO.Write,
+		CreatedAt:  tokenDTO.CreatedAt,
+		ExpiresAt:  tokenDTO.ExpiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to insert api token: %w", err)
+	}
+
+	tokenDTO.Id = result.InsertedID.(primitive.ObjectID).Hex()
+	return nil
+}
+
+func (m *MongoDb) GetAPITokenByHash(ctx context.Context, hash string) (*store.APIToken, error) {
+	var token apiToken
+	// Expired tokens are only cleaned up every so often, so don't count on them being gone.
+	err := m.tokenCol.FindOne(ctx, bson.M{"hash": hash, "expiresAt": bson.M{"$gt": time.Now()}}).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, store.NewNotFoundError("api token", hash)
+		}
+		return nil, fmt.Errorf("unexpected error getting api token: %w", err)
+	}
+	return toStoreAPIToken(&token), nil
+}
+
+func (m *MongoDb) GetAPITokens(ctx context.Context, userIdStr string) ([]*store.APIToken, error) {
+	userId, err := primitive.ObjectIDFromHex(userIdStr)
+	if err != nil {
+		return nil, store.NewBadRequestError(fmt.Sprintf("invalid user id: %s", userIdStr))
+	}
+
+	cursor, err := m.tokenCol.Find(
+		ctx,
+		bson.M{"userId": userId, "expiresAt": bson.M{"$gt": time.Now()}},
+		options.Find().SetSort(bson.M{"createdAt": 1}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find api tokens: %w", err)
+	}
+
+	var tokens []apiToken
+	err = cursor.All(ctx, &tokens)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode api tokens: %w", err)
+	}
+
+	storeTokens := make([]*store.APIToken, 0, len(tokens))
+	for _, token := range tokens {
+		storeTokens = append(storeTokens, toStoreAPIToken(&token))
+	}
+	return storeTokens, nil
+}
+
+func (m *MongoDb) DeleteAPIToken(ctx context.Context, userIdStr, tokenIdStr string) error {
+	userId, err := primitive.ObjectIDFromHex(userIdStr)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid user id: %s", userIdStr))
+	}
+	tokenId, err := primitive.ObjectIDFromHex(tokenIdStr)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid api token id: %s", tokenIdStr))
+	}
+
+	// Matching the user too means nobody can revoke someone else's token.
+	result, err := m.tokenCol.DeleteOne(ctx, bson.M{"_id": tokenId, "userId": userId})
+	if err != nil {
+		return fmt.Errorf("failed to delete api token: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return store.NewNotFoundError("api token", tokenIdStr)
+	}
+	return nil
+}
+
+func (m *MongoDb) TouchAPIToken(ctx context.Context, tokenIdStr string, at time.Time) error {
+	tokenId, err := primitive.ObjectIDFromHex(tokenIdStr)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid api token id: %s", tokenIdStr))
+	}
+
+	_, err = m.tokenCol.UpdateOne(ctx, bson.M{"_id": tokenId}, bson.M{"$set": bson.M{"lastUsedAt": at}})
+	if err != nil {
+		return fmt.Errorf("failed to update api token last used: %w", err)
+	}
+	return nil
+}
+
+func toStoreAPIToken(token *apiToken) *store.APIToken {
+	return &store.APIToken{
+		Id:         token.Id.Hex(),
+		UserId:     token.UserId.Hex(),
+		Name:       token.Name,
+		Hash:       token.Hash,
+		BoardNames: token.BoardNames,
+		Write:      token.Write,
+		CreatedAt:  token.CreatedAt,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+	}
+}
@@ -0,0 +1,141 @@
+package mdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/danharasymiw/danban/server/store"
+)
+
+func (m *MongoDb) AddUser(ctx context.Context, userDTO *store.User) error {
+	newUser := user{
+		Id:           primitive.NewObjectID(),
+		Email:        userDTO.Email,
+		Name:         userDTO.Name,
+		PasswordHash: userDTO.PasswordHash,
+		CreatedAt:    userDTO.CreatedAt,
+		OIDCIssuer:   userDTO.OIDCIssuer,
+		OIDCSubject:  userDTO.OIDCSubject,
+	}
+
+	_, err := m.userCol.InsertOne(ctx, newUser)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return store.NewBadRequestError("an account with that email already exists")
+		}
+		return fmt.Errorf("failed to insert user: %w", err)
+	}
+
+	userDTO.Id = newUser.Id.Hex()
+	return nil
+}
+
+func (m *MongoDb) GetUser(ctx context.Context, userIdStr string) (*store.User, error) {
+	userId, err := primitive.ObjectIDFromHex(userIdStr)
+	if err != nil {
+		return nil, store.NewBadRequestError(fmt.Sprintf("invalid user id: %s", userIdStr))
+	}
+	return m.findUser(ctx, bson.M{"_id": userId}, userIdStr)
+}
+
+func (m *MongoDb) GetUserByEmail(ctx context.Context, email string) (*store.User, error) {
+	return m.findUser(ctx, bson.M{"email": email}, email)
+}
+
+func (m *MongoDb) GetUserByOIDCSubject(ctx context.Context, issuer, subject string) (*store.User, error) {
+	return m.findUser(ctx, bson.M{"oidcIssuer": issuer, "oidcSubject": subject}, subject)
+}
+
+func (m *MongoDb) LinkUserOIDC(ctx context.Context, userIdStr, issuer, subject string) error {
+	userId, err := primitive.ObjectIDFromHex(userIdStr)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid user id: %s", userIdStr))
+	}
+
+	result, err := m.userCol.UpdateOne(
+		ctx,
+		bson.M{"_id": userId},
+		bson.M{"$set": bson.M{"oidcIssuer": issuer, "oidcSubject": subject}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to link user to oidc subject: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return store.NewNotFoundError("user", userIdStr)
+	}
+	return nil
+}
+
+func (m *MongoDb) findUser(ctx context.Context, filter bson.M, id string) (*store.User, error) {
+	var user user
+	err := m.userCol.FindOne(ctx, filter).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, store.NewNotFoundError("user", id)
+		}
+		return nil, fmt.Errorf("unexpected error getting user: %w", err)
+	}
+	return toStoreUser(&user), nil
+}
+
+func toStoreUser(u *user) *store.User {
+	return &store.User{
+		Id:           u.Id.Hex(),
+		Email:        u.Email,
+		Name:         u.Name,
+		PasswordHash: u.PasswordHash,
+		CreatedAt:    u.CreatedAt,
+		OIDCIssuer:   u.OIDCIssuer,
+		OIDCSubject:  u.OIDCSubject,
+	}
+}
+
+func (m *MongoDb) AddSession(ctx context.Context, sessionDTO *store.Session) error {
+	userId, err := primitive.ObjectIDFromHex(sessionDTO.UserId)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid user id: %s", sessionDTO.UserId))
+	}
+
+	_, err = m.sessionCol.InsertOne(ctx, session{
+		Id:        sessionDTO.Id,
+		UserId:    userId,
+		CreatedAt: sessionDTO.CreatedAt,
+		ExpiresAt: sessionDTO.ExpiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to insert session: %w", err)
+	}
+	return nil
+}
+
+func (m *MongoDb) GetSession(ctx context.Context, sessionId string) (*store.Session, error) {
+	var session session
+	// Expired sessions are only cleaned up every so often, so don't count on them being gone.
+	err := m.sessionCol.FindOne(ctx, bson.M{"_id": sessionId, "expiresAt": bson.M{"$gt": time.Now()}}).Decode(&session)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, store.NewNotFoundError("session", sessionId)
+		}
+		return nil, fmt.Errorf("unexpected error getting session: %w", err)
+	}
+
+	return &store.Session{
+		Id:        session.Id,
+		UserId:    session.UserId.Hex(),
+		CreatedAt: session.CreatedAt,
+		ExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+func (m *MongoDb) DeleteSession(ctx context.Context, sessionId string) error {
+	_, err := m.sessionCol.DeleteOne(ctx, bson.M{"_id": sessionId})
+	if err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package mdb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/danharasymiw/danban/server/store"
+)
+
+func (m *MongoDb) AddView(ctx context.Context, boardName string, viewDTO *store.View) error {
+	collapsedColumnIds, err := objectIds(viewDTO.CollapsedColumnIds, "column")
+	if err != nil {
+		return err
+	}
+
+	newView := view{
+		Id:                 primitive.NewObjectID(),
+		Name:               viewDTO.Name,
+		Filter:             viewDTO.Filter,
+		Assignee:           viewDTO.Assignee,
+		Sort:               viewDTO.Sort,
+		Lanes:              viewDTO.Lanes,
+		CollapsedColumnIds: collapsedColumnIds,
+	}
+
+	result, err := m.boardCol.UpdateOne(
+		ctx,
+		bson.M{"name": boardName},
+		bson.M{"$push": bson.M{"views": newView}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add view to board: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return store.NewNotFoundError("board", boardName)
+	}
+
+	viewDTO.Id = newView.Id.Hex()
+	return nil
+}
+
+func (m *MongoDb) DeleteView(ctx context.Context, boardName, viewIdStr string) error {
+	viewId, err := primitive.ObjectIDFromHex(viewIdStr)
+	if err != nil {
+		return store.NewBadRequestError(fmt.Sprintf("invalid view id: %s", viewIdStr))
+	}
+
+	result, err := m.boardCol.UpdateOne(
+		ctx,
+		bson.M{"name": boardName},
+		bson.M{"$pull": bson.M{"views": bson.M{"_id": viewId}}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove view from board: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return store.NewNotFoundError("board", boardName)
+	}
+	return nil
+}
+
+func (m *MongoDb) GetView(ctx context.Context, boardName, viewIdStr string) (*store.View, error) {
+	viewId, err := primitive.ObjectIDFromHex(viewIdStr)
+	if err != nil {
+		return nil, store.NewBadRequestError(fmt.Sprintf("invalid view id: %s", viewIdStr))
+	}
+
+	var board board
+	err = m.boardCol.FindOne(ctx, bson.M{"name": boardName}).Decode(&board)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, store.NewNotFoundError("board", boardName)
+		}
+		return nil, fmt.Errorf("unexpected error getting board views: %w", err)
+	}
+
+	for _, v := range toStoreViews(board.Views) {
+		if v.Id == viewId.Hex() {
+			return v, nil
+		}
+	}
+	return nil, store.NewNotFoundError("view", viewIdStr)
+}
+
+func toStoreViews(views []view) []*store.View {
+	storeViews := make([]*store.View, 0, len(views))
+	for _, v := range views {
+		collapsedColumnIds := make([]string, 0, len(v.CollapsedColumnIds))
+		for _, id := range v.CollapsedColumnIds {
+			collapsedColumnIds = append(collapsedColumnIds, id.Hex())
+		}
+		storeViews = append(storeViews, &store.View{
+			Id:                 v.Id.Hex(),
+			Name:               v.Name,
+			Filter:             v.Filter,
+			Assignee:           v.Assignee,
+			Sort:               v.Sort,
+			Lanes:              v.Lanes,
+			CollapsedColumnIds: collapsedColumnIds,
+		})
+	}
+	return storeViews
+}
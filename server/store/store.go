@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"time"
 )
 
 type Storage interface {
@@ -10,17 +11,99 @@ type Storage interface {
 	MoveCard(ctx context.Context, toColumnId, cardId string, index int) error
 	DeleteCard(ctx context.Context, columnId, cardId string, index int) error
 	GetCard(ctx context.Context, cardId string) (*Card, error)
-	GetCards(ctx context.Context, boardName, columnId, cardId string) ([]*Card, error)
+	ArchiveCard(ctx context.Context, cardId string) error
+	// ArchiveColumnCards archives every card in the column, returning how many there were.
+	ArchiveColumnCards(ctx context.Context, columnId string) (int, error)
+	RestoreCard(ctx context.Context, cardId string) error
+	GetArchivedCards(ctx context.Context, boardName string) ([]*Card, error)
+	// AutoArchiveCards archives the cards that have been in a done column for longer than their board allows.
+	AutoArchiveCards(ctx context.Context, now time.Time) (int, error)
+	GetCardByNumber(ctx context.Context, boardName string, number int) (*Card, error)
+	SearchCards(ctx context.Context, boardName, query string, limit int) ([]*Card, error)
+
+	AddChecklist(ctx context.Context, cardId string, checklist *Checklist) error
+	DeleteChecklist(ctx context.Context, cardId, checklistId string) error
+	AddChecklistItem(ctx context.Context, cardId, checklistId string, item *ChecklistItem) error
+	SetChecklistItemDone(ctx context.Context, cardId, checklistId, itemId string, done bool) error
+	DeleteChecklistItem(ctx context.Context, cardId, checklistId, itemId string) error
+
+	AddCardLink(ctx context.Context, cardId, otherCardId string, typ CardLinkType) error
+	DeleteCardLink(ctx context.Context, linkId string) error
+	GetCardLinks(ctx context.Context, cardId string) ([]*CardLink, error)
+
+	AddAttachment(ctx context.Context, cardId string, attachment *Attachment) error
+	DeleteAttachment(ctx context.Context, cardId, attachmentId string) error
+
+	AddComment(ctx context.Context, comment *Comment) error
+	EditComment(ctx context.Context, comment *Comment) error
+	DeleteComment(ctx context.Context, commentId string) error
+	GetComment(ctx context.Context, commentId string) (*Comment, error)
+	GetComments(ctx context.Context, cardId string) ([]*Comment, error)
 
 	AddColumn(ctx context.Context, boardName, column *Column) error
-	EditColumn(ctx context.Context, boardName, column *Column) error
+	EditColumn(ctx context.Context, boardName string, column *Column) error
 	MoveColumn(ctx context.Context, boardName, columnId string, index uint8) error
 	DeleteColumn(ctx context.Context, boardName, columnId string) error
 	GetColumn(ctx context.Context, columnId string) (*Column, error)
 	GetColumns(ctx context.Context, boardName string) ([]*Column, error)
+	// GetColumnBoardName finds the name of the board the column is on.
+	GetColumnBoardName(ctx context.Context, columnId string) (string, error)
 
 	AddBoard(ctx context.Context, board *Board) error
 	EditBoard(ctx context.Context, board *Board) error
 	DeleteBoard(ctx context.Context, boardName string) error
-	GetBoard(ctx context.Context, boardName string) (*Board, error)
+	SetAutoArchiveDays(ctx context.Context, boardName string, days int) error
+	GetBoard(ctx context.Context, boardName string, filter *CardFilter) (*Board, error)
+
+	GetBoardPasswordHash(ctx context.Context, boardName string) (string, error)
+	// SetBoardPassword locks the board with the password, as long as the setup token hash matches the one the board
+	// was made with. Each board's password can only be set once.
+	SetBoardPassword(ctx context.Context, boardName, setupTokenHash, passwordHash string) error
+
+	GetBoardRoles(ctx context.Context, boardName string) ([]*BoardRole, error)
+	// SetBoardRole gives the user the role on the board, replacing any role they already had.
+	SetBoardRole(ctx context.Context, boardName string, role *BoardRole) error
+	DeleteBoardRole(ctx context.Context, boardName, userId string) error
+	// ClaimBoard makes the user the owner of a board nobody owns yet, as long as the claim token is the board's.
+	ClaimBoard(ctx context.Context, boardName, claimTokenHash, userId string) error
+
+	AddShareLink(ctx context.Context, boardName string, link *ShareLink) error
+	DeleteShareLink(ctx context.Context, boardName, linkId string) error
+	GetShareLinks(ctx context.Context, boardName string) ([]*ShareLink, error)
+
+	AddLabel(ctx context.Context, boardName string, label *Label) error
+	DeleteLabel(ctx context.Context, boardName, labelId string) error
+	GetLabels(ctx context.Context, boardName string) ([]*Label, error)
+
+	AddMember(ctx context.Context, boardName string, member *Member) error
+	DeleteMember(ctx context.Context, boardName, memberId string) error
+	GetMembers(ctx context.Context, boardName string) ([]*Member, error)
+
+	AddView(ctx context.Context, boardName string, view *View) error
+	DeleteView(ctx context.Context, boardName, viewId string) error
+	GetView(ctx context.Context, boardName, viewId string) (*View, error)
+
+	// TryLock takes or renews the named lock for the holder until ttl has passed, reporting whether it has it.
+	TryLock(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+
+	// AddUser assigns the user an id, failing with a bad request if the email is already taken.
+	AddUser(ctx context.Context, user *User) error
+	GetUser(ctx context.Context, userId string) (*User, error)
+	GetUserByEmail(ctx context.Context, email string) (*User, error)
+	GetUserByOIDCSubject(ctx context.Context, issuer, subject string) (*User, error)
+	// LinkUserOIDC lets an existing user log in through the single sign-on provider.
+	LinkUserOIDC(ctx context.Context, userId, issuer, subject string) error
+
+	AddSession(ctx context.Context, session *Session) error
+	// GetSession only returns sessions that haven't expired yet.
+	GetSession(ctx context.Context, sessionId string) (*Session, error)
+	DeleteSession(ctx context.Context, sessionId string) error
+
+	AddAPIToken(ctx context.Context, token *APIToken) error
+	// GetAPITokenByHash only returns tokens that haven't expired yet.
+	GetAPITokenByHash(ctx context.Context, hash string) (*APIToken, error)
+	GetAPITokens(ctx context.Context, userId string) ([]*APIToken, error)
+	DeleteAPIToken(ctx context.Context, userId, tokenId string) error
+	// TouchAPIToken records when the token was last used.
+	TouchAPIToken(ctx context.Context, tokenId string, at time.Time) error
 }
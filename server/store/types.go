@@ -2,25 +2,312 @@ package store
 
 import (
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 )
 
 type Board struct {
 	Name    string
 	Columns []*Column
+	Labels  []*Label
+	Members []*Member
+	Views   []*View
+	Roles   []*BoardRole // Who can do what on the board, a board without any is open to everyone
+	// PasswordHash locks a board nobody owns to the people who know its password, empty leaves it open.
+	PasswordHash string
+	// PasswordSetupHash is a hash of the token that lets the board's creator set its password, until they do.
+	PasswordSetupHash string
+	// ClaimHash is a hash of the token that lets the board's creator take ownership of it, until someone does.
+	ClaimHash string
+	// AutoArchiveDays is how long cards sit in a done column before they're archived, zero leaves them there.
+	AutoArchiveDays int
 }
 
 type Column struct {
 	Id    string
 	Index int
 	Name  string
+	Done  bool // Finished cards end up in done columns
 	Cards []*Card
+	// CardCount is how many cards are in the column, including any left out of Cards by a filter.
+	CardCount int
 }
 
 type Card struct {
 	Id          string
+	ColumnId    string
+	Number      int // Sequential within the card's board, zero until one has been allocated
 	Index       int
 	Title       string
 	Description string
+	Labels      []*Label
+	Assignees   []*Member
+	StartDate   *time.Time
+	DueDate     *time.Time
+	Lane        string // The swimlane the card was put in by hand, empty when it isn't in one
+	Checklists  []*Checklist
+	Attachments []*Attachment
+	Blocked     bool       // Set when any card blocking this one isn't in a done column yet
+	ArchivedAt  *time.Time // Archived cards are left off the board until they're restored
+}
+
+type CardLinkType string
+
+const (
+	CardLinkBlocks       CardLinkType = "blocks"
+	CardLinkBlockedBy    CardLinkType = "blocked-by"
+	CardLinkDuplicates   CardLinkType = "duplicates"
+	CardLinkDuplicatedBy CardLinkType = "duplicated-by"
+	CardLinkRelatesTo    CardLinkType = "relates-to"
+)
+
+// CardLinkTypes lists every link type, in the order they're offered when linking cards.
+var CardLinkTypes = []CardLinkType{CardLinkBlocks, CardLinkBlockedBy, CardLinkDuplicates, CardLinkDuplicatedBy, CardLinkRelatesTo}
+
+// Inverse is the same link seen from the other card.
+func (t CardLinkType) Inverse() CardLinkType {
+	switch t {
+	case CardLinkBlocks:
+		return CardLinkBlockedBy
+	case CardLinkBlockedBy:
+		return CardLinkBlocks
+	case CardLinkDuplicates:
+		return CardLinkDuplicatedBy
+	case CardLinkDuplicatedBy:
+		return CardLinkDuplicates
+	default:
+		return t
+	}
+}
+
+// CardLink is a link seen from one of its cards, Type reads as "this card <Type> Card".
+type CardLink struct {
+	Id        string
+	Type      CardLinkType
+	Card      *Card
+	BoardName string
+}
+
+type Attachment struct {
+	Id           string
+	Name         string
+	ContentType  string
+	Size         int64
+	HasThumbnail bool
+	CreatedAt    time.Time
+}
+
+type Checklist struct {
+	Id    string
+	Name  string
+	Items []*ChecklistItem
+}
+
+type ChecklistItem struct {
+	Id   string
+	Text string
+	Done bool
+}
+
+// ChecklistProgress counts the done and total items across all of the card's checklists.
+func (c *Card) ChecklistProgress() (done int, total int) {
+	for _, checklist := range c.Checklists {
+		for _, item := range checklist.Items {
+			if item.Done {
+				done++
+			}
+			total++
+		}
+	}
+	return done, total
+}
+
+type Comment struct {
+	Id         string
+	CardId     string
+	AuthorId   string
+	AuthorName string
+	Body       string
+	CreatedAt  time.Time
+	EditedAt   *time.Time
+}
+
+type DueStatus string
+
+const (
+	DueStatusNone     DueStatus = ``
+	DueStatusUpcoming DueStatus = `upcoming`
+	DueStatusSoon     DueStatus = `soon`
+	DueStatusOverdue  DueStatus = `overdue`
+)
+
+// DueSoonWindow is how far ahead of its due date a card starts being flagged as due soon.
+const DueSoonWindow = 48 * time.Hour
+
+// DueStatus compares the card's due date, which has no time of day, against the day of now.
+func (c *Card) DueStatus(now time.Time) DueStatus {
+	if c.DueDate == nil {
+		return DueStatusNone
+	}
+
+	today := OverdueBefore(now)
+	switch {
+	case c.DueDate.Before(today):
+		return DueStatusOverdue
+	case c.DueDate.Sub(today) <= DueSoonWindow:
+		return DueStatusSoon
+	default:
+		return DueStatusUpcoming
+	}
+}
+
+// OverdueBefore is when cards due before it are overdue, which is the start of today since due dates are days in UTC.
+// The card badges and the due:overdue filter both use it, so they agree about which cards are overdue.
+func OverdueBefore(now time.Time) time.Time {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// CardRef is how people refer to a card, like TEAM-42.
+func CardRef(boardName string, number int) string {
+	return fmt.Sprintf("%s-%d", strings.ToUpper(boardName), number)
+}
+
+type Label struct {
+	Id    string
+	Name  string
+	Color string
+}
+
+type Member struct {
+	Id   string
+	Name string
+}
+
+// User is someone with an account, who logs in with their email and password.
+type User struct {
+	Id           string
+	Email        string
+	Name         string
+	PasswordHash string // Empty for users who only log in through single sign-on
+	CreatedAt    time.Time
+	// The single sign-on provider and its id for the user, if they've logged in through one.
+	OIDCIssuer  string
+	OIDCSubject string
+}
+
+// Session keeps a user logged in. The Id is a hash of the token in the user's cookie, so the stored sessions
+// can't be used to log in.
+type Session struct {
+	Id        string
+	UserId    string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// APIToken lets a user's scripts use the API as them, limited to some of their boards. Like sessions, only a hash
+// of the token is kept.
+type APIToken struct {
+	Id         string
+	UserId     string
+	Name       string // What the token is for
+	Hash       string
+	BoardNames []string // The boards the token can be used on
+	Write      bool     // Whether the token can change the boards, rather than only read them
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+}
+
+// Limit narrows down the user's role on the board to what the token allows. Tokens can't manage boards, so at
+// most they can edit them.
+func (t *APIToken) Limit(boardName string, role Role) Role {
+	if !slices.Contains(t.BoardNames, boardName) || role == `` {
+		return ``
+	}
+	if !t.Write {
+		return RoleViewer
+	}
+	if role == RoleOwner {
+		return RoleEditor
+	}
+	return role
+}
+
+// Role is what a user can do on a board, each role can do everything the roles after it can.
+type Role string
+
+const (
+	RoleOwner     Role = "owner"     // Can also change who has which role
+	RoleEditor    Role = "editor"    // Can change anything on the board
+	RoleCommenter Role = "commenter" // Can comment on cards
+	RoleViewer    Role = "viewer"    // Can only look
+)
+
+// Roles lists every role, from most to least allowed.
+var Roles = []Role{RoleOwner, RoleEditor, RoleCommenter, RoleViewer}
+
+// Allows reports whether someone with the role can do what needs the other role. The empty role allows nothing.
+func (r Role) Allows(needed Role) bool {
+	have := slices.Index(Roles, r)
+	return have >= 0 && have <= slices.Index(Roles, needed)
+}
+
+// BoardRole gives a user a role on a board.
+type BoardRole struct {
+	UserId string
+	Role   Role
+}
+
+// ShareLink lets anyone who has it look at a board without being able to change it, until it's revoked.
+// Only a hash of the link's token is kept, like sessions.
+type ShareLink struct {
+	Id        string
+	Name      string // Who the link was made for
+	TokenHash string
+	CreatedAt time.Time
+}
+
+// View is a saved way of looking at a board, so it can be picked again or shared by its URL.
+type View struct {
+	Id                 string
+	Name               string
+	Filter             string // A filter query, see the filter package
+	Assignee           string
+	Sort               string
+	Lanes              string // What the board's swimlanes are split by, if anything
+	CollapsedColumnIds []string
+}
+
+// CardFilter narrows down which cards are returned with a board, a nil filter returns every card.
+// A card has to match every field that's set, and at least one of the values in each list.
+// Names are matched case insensitively against the board's labels, members and columns.
+type CardFilter struct {
+	// AssigneeId is the member picked in the board's toolbar. It's checked on its own, so it narrows
+	// down the assignees in the filter query rather than adding to them.
+	AssigneeId string
+
+	AssigneeIds          []string
+	ExcludeAssigneeIds   []string
+	AssigneeNames        []string
+	ExcludeAssigneeNames []string
+	HasAssignees         *bool
+
+	LabelNames        []string
+	ExcludeLabelNames []string
+	HasLabels         *bool
+
+	ColumnNames        []string
+	ExcludeColumnNames []string
+
+	DueBefore  *time.Time
+	DueAfter   *time.Time
+	HasDueDate *bool
+
+	// Words have to appear in the card's title or description, ExcludeWords must not.
+	Words        []string
+	ExcludeWords []string
 }
 
 type NotFoundError struct {
@@ -60,3 +347,17 @@ func NewBadRequestError(issue string) *BadRequestError {
 func (e *BadRequestError) Error() string {
 	return e.issue
 }
+
+type ForbiddenError struct {
+	issue string
+}
+
+func NewForbiddenError(issue string) *ForbiddenError {
+	return &ForbiddenError{
+		issue: issue,
+	}
+}
+
+func (e *ForbiddenError) Error() string {
+	return e.issue
+}
@@ -0,0 +1,72 @@
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+)
+
+// MaxPixels guards against images that are small on disk but enormous once decoded.
+const MaxPixels = 40_000_000
+
+var ErrTooLarge = errors.New("image is too large to thumbnail")
+
+// Supported reports whether a thumbnail can be made from the content type.
+func Supported(contentType string) bool {
+	switch contentType {
+	case "image/png", "image/jpeg", "image/gif":
+		return true
+	}
+	return false
+}
+
+// Make decodes an image and scales it down to fit within a square of size pixels, encoded as a PNG.
+func Make(data []byte, size int) ([]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image config: %w", err)
+	}
+	if config.Width*config.Height > MaxPixels {
+		return nil, ErrTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	var buf bytes.Buffer
+	err = png.Encode(&buf, scale(src, size))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// scale shrinks the image with nearest neighbour sampling, which is plenty for a thumbnail.
+func scale(src image.Image, size int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= size && height <= size {
+		return src
+	}
+
+	newWidth, newHeight := size, size
+	if width > height {
+		newHeight = max(1, height*size/width)
+	} else {
+		newWidth = max(1, width*size/height)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
+	for y := 0; y < newHeight; y++ {
+		for x := 0; x < newWidth; x++ {
+			dst.Set(x, y, src.At(bounds.Min.X+x*width/newWidth, bounds.Min.Y+y*height/newHeight))
+		}
+	}
+	return dst
+}